@@ -0,0 +1,224 @@
+package slimarray
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"io"
+)
+
+var (
+	InvalidLengthPrefix = errors.New("length prefix must be 1, 2, 4 or 8 bytes")
+)
+
+// BytesBuilder creates a SlimBytes by adding records one by one.
+// A record is copied into the packed record buffer and its position is
+// compressed as soon as a segment of positions is full.
+// Thus the caller does not need to keep every record in memory.
+//
+// Since 0.1.15
+type BytesBuilder struct {
+	positions *u64Builder
+	records   []byte
+}
+
+// NewBytesBuilder creates a BytesBuilder.
+// sizeHint is the expected total size of all records, to reduce re-allocation
+// of the record buffer. It could be 0.
+//
+// Since 0.1.15
+func NewBytesBuilder(sizeHint int) *BytesBuilder {
+	b := &BytesBuilder{
+		positions: newU64Builder(),
+		records:   make([]byte, 0, sizeHint),
+	}
+
+	// the start position of the first record.
+	b.positions.add(0)
+	return b
+}
+
+// Add appends a record.
+//
+// Since 0.1.15
+func (b *BytesBuilder) Add(rec []byte) error {
+	if b.full() {
+		return TooManyRows
+	}
+
+	b.records = append(b.records, rec...)
+	b.endRecord()
+	return nil
+}
+
+// endRecord adds the end position of the last record.
+func (b *BytesBuilder) endRecord() {
+	b.positions.add(uint64(len(b.records)))
+}
+
+// full returns true if no more record can be added: there are n+1 positions
+// for n records and the count of positions must not exceed max value of int32.
+func (b *BytesBuilder) full() bool {
+	return b.positions.sm.N == 0x7fffffff
+}
+
+// Len returns the number of records added.
+//
+// Since 0.1.15
+func (b *BytesBuilder) Len() int {
+	return int(b.positions.sm.N) - 1
+}
+
+// Finish creates the SlimBytes.
+// The BytesBuilder can not be used any more after Finish.
+//
+// Since 0.1.15
+func (b *BytesBuilder) Finish() *SlimBytes {
+
+	size := uint64(len(b.records))
+
+	positions := b.positions.finish()
+	if size <= 0xffffffff {
+		// A 64-bit SlimArray with small elts is also a valid 32-bit SlimArray.
+		positions.EltWidth = 0
+	}
+
+	bs := &SlimBytes{
+		Positions: positions,
+		Records:   b.records,
+	}
+
+	b.positions = nil
+	b.records = nil
+
+	return bs
+}
+
+// NewBytesFromLines creates a SlimBytes from lines read from r.
+// Lines are split in the same way as bufio.ScanLines: the trailing end-of-line
+// marker "\n" or "\r\n" is stripped and the last line may have no newline.
+//
+// Since 0.1.15
+func NewBytesFromLines(r io.Reader) (*SlimBytes, error) {
+
+	b := NewBytesBuilder(0)
+	br := bufio.NewReader(r)
+
+	for {
+		if b.full() {
+			return nil, TooManyRows
+		}
+
+		start := len(b.records)
+		for {
+			line, err := br.ReadSlice('\n')
+			b.records = append(b.records, line...)
+
+			if err == bufio.ErrBufferFull {
+				continue
+			}
+
+			if err == io.EOF {
+				if len(b.records) > start {
+					l := len(b.records)
+					if b.records[l-1] == '\r' {
+						b.records = b.records[:l-1]
+					}
+					b.endRecord()
+				}
+				return b.Finish(), nil
+			}
+
+			if err != nil {
+				return nil, err
+			}
+			break
+		}
+
+		// strip "\n" or "\r\n"
+		l := len(b.records) - 1
+		if l > start && b.records[l-1] == '\r' {
+			l--
+		}
+		b.records = b.records[:l]
+		b.endRecord()
+	}
+}
+
+// NewBytesFromFrames creates a SlimBytes from length-prefixed frames read
+// from r.
+// A frame is a big-endian unsigned length of lengthPrefix bytes followed by the
+// record. lengthPrefix must be 1, 2, 4 or 8.
+//
+// A frame with incomplete length or record results in io.ErrUnexpectedEOF.
+//
+// Since 0.1.15
+func NewBytesFromFrames(r io.Reader, lengthPrefix int) (*SlimBytes, error) {
+
+	switch lengthPrefix {
+	case 1, 2, 4, 8:
+	default:
+		return nil, InvalidLengthPrefix
+	}
+
+	b := NewBytesBuilder(0)
+	br := bufio.NewReader(r)
+	lbuf := make([]byte, 8)
+
+	for {
+		_, err := io.ReadFull(br, lbuf[8-lengthPrefix:])
+		if err == io.EOF {
+			return b.Finish(), nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if b.full() {
+			return nil, TooManyRows
+		}
+
+		l := binary.BigEndian.Uint64(lbuf)
+
+		// Read the record directly into the record buffer.
+		// The buffer does not grow more than 1 MB a time, in case a corrupted
+		// length is read.
+		for l > 0 {
+			n := l
+			if n > 1<<20 {
+				n = 1 << 20
+			}
+
+			start := len(b.records)
+			b.records = grow(b.records, int(n))
+			_, err = io.ReadFull(br, b.records[start:])
+			if err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+			if err != nil {
+				return nil, err
+			}
+
+			l -= n
+		}
+
+		b.endRecord()
+	}
+}
+
+// grow extends len(bs) by n, with amortized re-allocation.
+func grow(bs []byte, n int) []byte {
+	l := len(bs) + n
+	if l <= cap(bs) {
+		return bs[:l]
+	}
+
+	c := cap(bs) * 2
+	if c < l {
+		c = l
+	}
+
+	rst := make([]byte, l, c)
+	copy(rst, bs)
+	return rst
+}
@@ -0,0 +1,132 @@
+package slimarray
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/openacid/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBytesBuilder(t *testing.T) {
+
+	ta := require.New(t)
+
+	cases := []int{0, 1, 1023, 1024, 1025, 1024 * 10}
+
+	for _, n := range cases {
+		t.Run(fmt.Sprintf("n=%d", n), func(t *testing.T) {
+
+			records := testutil.RandBytesSlice(n, 0, 20)
+
+			b := NewBytesBuilder(0)
+			for _, rec := range records {
+				err := b.Add(rec)
+				ta.NoError(err)
+			}
+			ta.Equal(n, b.Len())
+
+			sb := b.Finish()
+			ta.Equal(int32(0), sb.Positions.EltWidth)
+			ta.Equal(n+1, sb.Positions.Len())
+
+			want, err := NewBytes(records)
+			ta.NoError(err)
+			ta.Equal(want.Records, sb.Records)
+
+			for i, rec := range records {
+				ta.Equal(rec, sb.Get(int32(i)))
+			}
+		})
+	}
+}
+
+func TestNewBytesFromLines(t *testing.T) {
+
+	ta := require.New(t)
+
+	long := strings.Repeat("x", 10000)
+
+	cases := []struct {
+		input string
+		want  []string
+	}{
+		{"", []string{}},
+		{"\n", []string{""}},
+		{"a", []string{"a"}},
+		{"a\n", []string{"a"}},
+		{"a\r\nb\r", []string{"a", "b"}},
+		{"a\n\nbc\n", []string{"a", "", "bc"}},
+		{"\r\n\r\n", []string{"", ""}},
+		{long + "\n" + long, []string{long, long}},
+	}
+
+	for i, c := range cases {
+		sb, err := NewBytesFromLines(strings.NewReader(c.input))
+		ta.NoError(err)
+
+		got := []string{}
+		for j := 0; j < sb.Positions.Len()-1; j++ {
+			got = append(got, string(sb.Get(int32(j))))
+		}
+		ta.Equal(c.want, got, "%d-th: case: %+v", i+1, c)
+	}
+}
+
+func TestNewBytesFromFrames(t *testing.T) {
+
+	ta := require.New(t)
+
+	records := testutil.RandBytesSlice(3000, 0, 200)
+
+	for _, prefix := range []int{1, 2, 4, 8} {
+
+		buf := frames(records, prefix)
+
+		sb, err := NewBytesFromFrames(bytes.NewReader(buf), prefix)
+		ta.NoError(err)
+		ta.Equal(len(records)+1, sb.Positions.Len())
+
+		for i, rec := range records {
+			ta.Equal(rec, sb.Get(int32(i)))
+		}
+
+		// truncated length
+		if prefix > 1 {
+			truncated := append(buf, make([]byte, prefix-1)...)
+			_, err = NewBytesFromFrames(bytes.NewReader(truncated), prefix)
+			ta.Equal(io.ErrUnexpectedEOF, err)
+		}
+
+		// truncated record
+		_, err = NewBytesFromFrames(bytes.NewReader(buf[:len(buf)-1]), prefix)
+		ta.Equal(io.ErrUnexpectedEOF, err)
+	}
+
+	{
+		// corrupted length
+		buf := []byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 1, 2, 3}
+		_, err := NewBytesFromFrames(bytes.NewReader(buf), 8)
+		ta.Equal(io.ErrUnexpectedEOF, err)
+	}
+
+	for _, prefix := range []int{-1, 0, 3, 16} {
+		_, err := NewBytesFromFrames(bytes.NewReader(nil), prefix)
+		ta.Equal(InvalidLengthPrefix, err)
+	}
+}
+
+func frames(records [][]byte, prefix int) []byte {
+	buf := []byte{}
+	lbuf := make([]byte, 8)
+	for _, rec := range records {
+		binary.BigEndian.PutUint64(lbuf, uint64(len(rec)))
+		buf = append(buf, lbuf[8-prefix:]...)
+		buf = append(buf, rec...)
+	}
+	return buf
+}
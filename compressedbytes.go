@@ -0,0 +1,411 @@
+package slimarray
+
+import (
+	"bytes"
+	"compress/flate"
+	"container/list"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"sync"
+)
+
+const (
+	// DefaultCompressBlockSize is the default uncompressed size of a block in
+	// CompressedBytes.
+	DefaultCompressBlockSize = 32 * 1024
+)
+
+var (
+	// UnknownCodec is returned if no codec is registered with the name a
+	// CompressedBytes uses.
+	//
+	// Since 0.1.15
+	UnknownCodec = errors.New("unknown codec")
+
+	// DecompressedSizeMismatch is returned if the decompressed size of a block
+	// is not the size of the original data.
+	//
+	// Since 0.1.15
+	DecompressedSizeMismatch = errors.New("decompressed size mismatch")
+)
+
+// Codec compresses and decompresses a block of records for CompressedBytes.
+//
+// Since 0.1.15
+type Codec interface {
+	// Name is the unique name of a codec. It is stored in CompressedBytes to
+	// find the codec to decompress blocks.
+	Name() string
+
+	// Compress returns the compressed data of src.
+	Compress(src []byte) ([]byte, error)
+
+	// Decompress returns the original data of compressed data src.
+	// size is the length of the original data.
+	Decompress(src []byte, size int) ([]byte, error)
+}
+
+var (
+	codecsMu sync.RWMutex
+	codecs   = map[string]Codec{}
+)
+
+func init() {
+	RegisterCodec(FlateCodec{Level: flate.DefaultCompression})
+	RegisterCodec(NoCodec{})
+}
+
+// RegisterCodec makes a codec available to CompressedBytes by its name.
+// A codec must be registered before decompressing blocks compressed by it,
+// e.g., before reading a CompressedBytes unmarshaled from protobuf.
+//
+// Since 0.1.15
+func RegisterCodec(c Codec) {
+	codecsMu.Lock()
+	defer codecsMu.Unlock()
+
+	codecs[c.Name()] = c
+}
+
+func getCodec(name string) (Codec, error) {
+	codecsMu.RLock()
+	defer codecsMu.RUnlock()
+
+	c, ok := codecs[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", UnknownCodec, name)
+	}
+	return c, nil
+}
+
+// FlateCodec is a Codec with compress/flate. It is the default codec.
+// Level specifies the compression level when compressing.
+//
+// Since 0.1.15
+type FlateCodec struct {
+	Level int
+}
+
+// Name returns "flate".
+//
+// Since 0.1.15
+func (c FlateCodec) Name() string { return "flate" }
+
+// Compress src with flate.
+//
+// Since 0.1.15
+func (c FlateCodec) Compress(src []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	w, err := flate.NewWriter(&buf, c.Level)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = w.Write(src)
+	if err != nil {
+		return nil, err
+	}
+
+	err = w.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// Decompress flate compressed data.
+// It reads at most size+1 bytes, thus a corrupted block does not grow without
+// bound, and the caller still sees a too long block.
+//
+// Since 0.1.15
+func (c FlateCodec) Decompress(src []byte, size int) ([]byte, error) {
+	r := flate.NewReader(bytes.NewReader(src))
+	defer r.Close()
+
+	return ioutil.ReadAll(io.LimitReader(r, int64(size)+1))
+}
+
+// NoCodec is a Codec that does not compress.
+//
+// Since 0.1.15
+type NoCodec struct{}
+
+// Name returns "none".
+//
+// Since 0.1.15
+func (c NoCodec) Name() string { return "none" }
+
+// Compress returns src.
+//
+// Since 0.1.15
+func (c NoCodec) Compress(src []byte) ([]byte, error) {
+	return append([]byte{}, src...), nil
+}
+
+// Decompress returns src.
+//
+// Since 0.1.15
+func (c NoCodec) Decompress(src []byte, size int) ([]byte, error) {
+	return src, nil
+}
+
+// CompressedBytesOpt specifies how to build a CompressedBytes.
+//
+// Since 0.1.15
+type CompressedBytesOpt struct {
+	// BlockSize is the uncompressed size of a block.
+	// Records are added to a block until the block size reaches BlockSize.
+	// A record is never split into two blocks.
+	// By default it is DefaultCompressBlockSize.
+	BlockSize int
+
+	// Codec to compress blocks. By default it is FlateCodec.
+	// It must be registered with RegisterCodec() before creating a
+	// CompressedBytes.
+	Codec Codec
+}
+
+// NewCompressedBytes creates a CompressedBytes from records.
+// opt could be nil to use default options.
+//
+// It returns an error wrapping UnknownCodec if opt.Codec is not registered.
+//
+// Since 0.1.15
+func NewCompressedBytes(records [][]byte, opt *CompressedBytesOpt) (*CompressedBytes, error) {
+
+	blockSize := DefaultCompressBlockSize
+	var codec Codec = FlateCodec{Level: flate.DefaultCompression}
+
+	if opt != nil {
+		if opt.BlockSize > 0 {
+			blockSize = opt.BlockSize
+		}
+		if opt.Codec != nil {
+			codec = opt.Codec
+			_, err := getCodec(codec.Name())
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if int64(len(records)) > 0x7fffffff-1 {
+		return nil, TooManyRows
+	}
+
+	n := len(records)
+	positions := make([]uint64, 0, n+1)
+	blocks := make([]uint32, 0, n)
+	blockStarts := []uint64{0}
+	blockOffsets := []uint64{0}
+
+	var data []byte
+	var blk []byte
+	pos := uint64(0)
+
+	flush := func() error {
+		if len(blk) == 0 {
+			return nil
+		}
+
+		compressed, err := codec.Compress(blk)
+		if err != nil {
+			return err
+		}
+
+		data = append(data, compressed...)
+		blockStarts = append(blockStarts, pos)
+		blockOffsets = append(blockOffsets, uint64(len(data)))
+		blk = blk[:0]
+		return nil
+	}
+
+	for _, rec := range records {
+		positions = append(positions, pos)
+		blocks = append(blocks, uint32(len(blockStarts)-1))
+
+		blk = append(blk, rec...)
+		pos += uint64(len(rec))
+
+		if len(blk) >= blockSize {
+			err := flush()
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	err := flush()
+	if err != nil {
+		return nil, err
+	}
+	positions = append(positions, pos)
+
+	cb := &CompressedBytes{
+		Codec:        codec.Name(),
+		Positions:    NewU64(positions),
+		Blocks:       NewU32(blocks),
+		BlockStarts:  NewU64(blockStarts),
+		BlockOffsets: NewU64(blockOffsets),
+		Data:         data,
+	}
+
+	return cb, nil
+}
+
+// Len returns the number of records.
+//
+// Since 0.1.15
+func (cb *CompressedBytes) Len() int {
+	return cb.Positions.Len() - 1
+}
+
+// Get returns the i-th record.
+// It decompresses the block the record is in.
+//
+// Since 0.1.15
+func (cb *CompressedBytes) Get(i int32) ([]byte, error) {
+	if cb.isEmpty(i) {
+		return []byte{}, nil
+	}
+
+	blkIdx := int32(cb.Blocks.Get(i))
+	blk, err := cb.Block(blkIdx)
+	if err != nil {
+		return nil, err
+	}
+	return cb.recordInBlock(blk, blkIdx, i), nil
+}
+
+// Block returns the uncompressed data of the i-th block.
+// It returns an error wrapping DecompressedSizeMismatch if the codec returns
+// a block of a wrong size, e.g., the data is corrupted.
+//
+// Since 0.1.15
+func (cb *CompressedBytes) Block(i int32) ([]byte, error) {
+
+	codec, err := getCodec(cb.Codec)
+	if err != nil {
+		return nil, err
+	}
+
+	s, e := cb.BlockOffsets.Get2U64(i)
+	us, ue := cb.BlockStarts.Get2U64(i)
+
+	blk, err := codec.Decompress(cb.Data[s:e], int(ue-us))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(blk) != int(ue-us) {
+		return nil, fmt.Errorf("%w: block: %d, expect: %d, got: %d",
+			DecompressedSizeMismatch, i, ue-us, len(blk))
+	}
+
+	return blk, nil
+}
+
+// isEmpty returns true if the i-th record is empty.
+// An empty record after the last block refers to a block that does not exist.
+func (cb *CompressedBytes) isEmpty(i int32) bool {
+	s, e := cb.Positions.Get2U64(i)
+	return s == e
+}
+
+// recordInBlock extracts the i-th record from the uncompressed block blk.
+func (cb *CompressedBytes) recordInBlock(blk []byte, blkIdx, i int32) []byte {
+	base := cb.BlockStarts.GetU64(blkIdx)
+	s, e := cb.Positions.Get2U64(i)
+	return blk[s-base : e-base]
+}
+
+// BlockCache caches recently used uncompressed blocks of a CompressedBytes
+// with LRU policy.
+// It is safe to use a BlockCache concurrently.
+//
+// Since 0.1.15
+type BlockCache struct {
+	cb       *CompressedBytes
+	capacity int
+
+	mu     sync.Mutex
+	lru    *list.List
+	blocks map[int32]*list.Element
+}
+
+type cachedBlock struct {
+	idx  int32
+	data []byte
+}
+
+// NewBlockCache creates a BlockCache that keeps at most capacity uncompressed
+// blocks.
+//
+// Since 0.1.15
+func NewBlockCache(cb *CompressedBytes, capacity int) *BlockCache {
+	if capacity < 1 {
+		capacity = 1
+	}
+
+	return &BlockCache{
+		cb:       cb,
+		capacity: capacity,
+		lru:      list.New(),
+		blocks:   make(map[int32]*list.Element, capacity),
+	}
+}
+
+// Get returns the i-th record.
+// The returned slice refers to the cached block and must not be modified.
+//
+// Since 0.1.15
+func (c *BlockCache) Get(i int32) ([]byte, error) {
+	if c.cb.isEmpty(i) {
+		return []byte{}, nil
+	}
+
+	blkIdx := int32(c.cb.Blocks.Get(i))
+	blk, err := c.block(blkIdx)
+	if err != nil {
+		return nil, err
+	}
+	return c.cb.recordInBlock(blk, blkIdx, i), nil
+}
+
+func (c *BlockCache) block(blkIdx int32) ([]byte, error) {
+
+	c.mu.Lock()
+	if e, ok := c.blocks[blkIdx]; ok {
+		c.lru.MoveToFront(e)
+		c.mu.Unlock()
+		return e.Value.(*cachedBlock).data, nil
+	}
+	c.mu.Unlock()
+
+	blk, err := c.cb.Block(blkIdx)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if e, ok := c.blocks[blkIdx]; ok {
+		// added by another goroutine
+		c.lru.MoveToFront(e)
+		return e.Value.(*cachedBlock).data, nil
+	}
+
+	c.blocks[blkIdx] = c.lru.PushFront(&cachedBlock{idx: blkIdx, data: blk})
+
+	if c.lru.Len() > c.capacity {
+		last := c.lru.Back()
+		c.lru.Remove(last)
+		delete(c.blocks, last.Value.(*cachedBlock).idx)
+	}
+
+	return blk, nil
+}
@@ -0,0 +1,302 @@
+package slimarray
+
+import (
+	"compress/flate"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/openacid/low/size"
+	"github.com/openacid/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewCompressedBytes(t *testing.T) {
+
+	ta := require.New(t)
+
+	cases := []struct {
+		n         int
+		blockSize int
+		codec     Codec
+	}{
+		{0, 0, nil},
+		{1, 0, nil},
+		{1000, 0, nil},
+		{1000, 1, nil},
+		{1000, 100, nil},
+		{1000, 100, NoCodec{}},
+		{1024 * 10, 4096, nil},
+	}
+
+	for i, c := range cases {
+		records := testutil.RandBytesSlice(c.n, 0, 50)
+
+		cb, err := NewCompressedBytes(records, &CompressedBytesOpt{
+			BlockSize: c.blockSize,
+			Codec:     c.codec,
+		})
+		ta.NoError(err)
+		ta.Equal(c.n, cb.Len(), "%d-th: case: %+v", i+1, c)
+
+		for j, rec := range records {
+			got, err := cb.Get(int32(j))
+			ta.NoError(err)
+			ta.Equal(rec, got, "%d-th: case: %+v, j=%d", i+1, c, j)
+		}
+	}
+}
+
+func TestNewCompressedBytes_emptyRecords(t *testing.T) {
+
+	ta := require.New(t)
+
+	// Empty records after the last block do not belong to any block.
+	cases := [][][]byte{
+		{{}},
+		{{}, {}, {}},
+		{[]byte("foo"), {}},
+		{{}, []byte("foo"), {}, {}},
+	}
+
+	for i, records := range cases {
+		cb, err := NewCompressedBytes(records, &CompressedBytesOpt{BlockSize: 1})
+		ta.NoError(err)
+
+		c := NewBlockCache(cb, 2)
+
+		for j, rec := range records {
+			got, err := cb.Get(int32(j))
+			ta.NoError(err)
+			ta.Equal(rec, got, "%d-th: j=%d", i+1, j)
+
+			got, err = c.Get(int32(j))
+			ta.NoError(err)
+			ta.Equal(rec, got, "%d-th: j=%d", i+1, j)
+		}
+	}
+}
+
+func TestCompressedBytes_ratio(t *testing.T) {
+
+	ta := require.New(t)
+
+	records := [][]byte{}
+	for i := 0; i < 1024*10; i++ {
+		rec := fmt.Sprintf(`{"id": %d, "name": "user-%d", "tags": ["a", "b"]}`, i, i%100)
+		records = append(records, []byte(rec))
+	}
+
+	plain, err := NewBytes(records)
+	ta.NoError(err)
+
+	cb, err := NewCompressedBytes(records, nil)
+	ta.NoError(err)
+	ta.Equal("flate", cb.Codec)
+
+	fmt.Println("plain:", size.Of(plain), "compressed:", size.Of(cb))
+	ta.Less(size.Of(cb)*4, size.Of(plain))
+}
+
+func TestCompressedBytes_marshalUnmarshal(t *testing.T) {
+
+	ta := require.New(t)
+
+	records := testutil.RandBytesSlice(1000, 0, 50)
+	cb, err := NewCompressedBytes(records, &CompressedBytesOpt{BlockSize: 100})
+	ta.NoError(err)
+
+	bs, err := proto.Marshal(cb)
+	ta.NoError(err)
+
+	b := &CompressedBytes{}
+	err = proto.Unmarshal(bs, b)
+	ta.NoError(err)
+
+	for i, rec := range records {
+		got, err := b.Get(int32(i))
+		ta.NoError(err)
+		ta.Equal(rec, got)
+	}
+
+	// an empty record does not decompress any block.
+	i := 0
+	for len(records[i]) == 0 {
+		i++
+	}
+
+	b.Codec = "foo"
+	_, err = b.Get(int32(i))
+	ta.True(errors.Is(err, UnknownCodec))
+}
+
+func TestFlateCodec_Decompress(t *testing.T) {
+
+	ta := require.New(t)
+
+	c := FlateCodec{Level: flate.DefaultCompression}
+	src := []byte("foobarfoobar")
+
+	compressed, err := c.Compress(src)
+	ta.NoError(err)
+
+	got, err := c.Decompress(compressed, len(src))
+	ta.NoError(err)
+	ta.Equal(src, got)
+
+	// reads at most size+1 bytes
+	got, err = c.Decompress(compressed, 3)
+	ta.NoError(err)
+	ta.Equal(src[:4], got)
+}
+
+// shortCodec loses the last byte when decompressing.
+type shortCodec struct {
+	NoCodec
+}
+
+func (c shortCodec) Name() string { return "short" }
+
+func (c shortCodec) Decompress(src []byte, size int) ([]byte, error) {
+	return src[:len(src)-1], nil
+}
+
+func TestCompressedBytes_Block_sizeMismatch(t *testing.T) {
+
+	ta := require.New(t)
+
+	RegisterCodec(shortCodec{})
+
+	records := testutil.RandBytesSlice(100, 10, 11)
+	cb, err := NewCompressedBytes(records, &CompressedBytesOpt{
+		BlockSize: 100,
+		Codec:     shortCodec{},
+	})
+	ta.NoError(err)
+
+	last := int32(cb.Len()) - 1
+	_, err = cb.Block(int32(cb.Blocks.Get(last)))
+	ta.True(errors.Is(err, DecompressedSizeMismatch), "err: %v", err)
+
+	_, err = cb.Get(last)
+	ta.True(errors.Is(err, DecompressedSizeMismatch), "err: %v", err)
+
+	_, err = NewBlockCache(cb, 2).Get(last)
+	ta.True(errors.Is(err, DecompressedSizeMismatch), "err: %v", err)
+}
+
+// countingCodec counts the number of decompressed blocks.
+type countingCodec struct {
+	NoCodec
+	mu  sync.Mutex
+	cnt int
+}
+
+func (c *countingCodec) Name() string { return "counting" }
+
+func (c *countingCodec) Decompress(src []byte, size int) ([]byte, error) {
+	c.mu.Lock()
+	c.cnt++
+	c.mu.Unlock()
+	return src, nil
+}
+
+func TestBlockCache(t *testing.T) {
+
+	ta := require.New(t)
+
+	codec := &countingCodec{}
+
+	// 10 byte per record, 10 record per block.
+	records := testutil.RandBytesSlice(1000, 10, 11)
+	opt := &CompressedBytesOpt{
+		BlockSize: 100,
+		Codec:     codec,
+	}
+
+	// a codec must be registered before use
+	_, err := NewCompressedBytes(records, opt)
+	ta.True(errors.Is(err, UnknownCodec))
+
+	RegisterCodec(codec)
+	cb, err := NewCompressedBytes(records, opt)
+	ta.NoError(err)
+
+	c := NewBlockCache(cb, 2)
+
+	get := func(i int) {
+		got, err := c.Get(int32(i))
+		ta.NoError(err)
+		ta.Equal(records[i], got)
+	}
+
+	get(0)
+	get(9)
+	ta.Equal(1, codec.cnt)
+
+	get(10)
+	ta.Equal(2, codec.cnt)
+
+	get(1)
+	ta.Equal(2, codec.cnt)
+
+	// evict block 1
+	get(20)
+	ta.Equal(3, codec.cnt)
+
+	get(2)
+	ta.Equal(3, codec.cnt)
+
+	get(11)
+	ta.Equal(4, codec.cnt)
+
+	// concurrent
+	var wg sync.WaitGroup
+	for k := 0; k < 4; k++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i, rec := range records {
+				got, err := c.Get(int32(i))
+				ta.NoError(err)
+				ta.Equal(rec, got)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+var OutputCompressedBytes int
+
+func BenchmarkCompressedBytes_Get(b *testing.B) {
+
+	records := [][]byte{}
+	for i := 0; i < 1024*64; i++ {
+		rec := fmt.Sprintf(`{"id": %d, "name": "user-%d"}`, i, i%100)
+		records = append(records, []byte(rec))
+	}
+	mask := len(records) - 1
+
+	cb, _ := NewCompressedBytes(records, &CompressedBytesOpt{BlockSize: 4096})
+
+	b.Run("nocache", func(b *testing.B) {
+		s := 0
+		for i := 0; i < b.N; i++ {
+			rec, _ := cb.Get(int32(i & mask))
+			s += len(rec)
+		}
+		OutputCompressedBytes = s
+	})
+
+	b.Run("cache", func(b *testing.B) {
+		c := NewBlockCache(cb, 16)
+		s := 0
+		for i := 0; i < b.N; i++ {
+			rec, _ := c.Get(int32(i & mask))
+			s += len(rec)
+		}
+		OutputCompressedBytes = s
+	})
+}
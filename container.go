@@ -0,0 +1,396 @@
+package slimarray
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"io/ioutil"
+	"math"
+
+	"github.com/golang/protobuf/proto"
+)
+
+const (
+	// ContainerVersion is the latest container format version.
+	//
+	// Since 0.1.15
+	ContainerVersion = 1
+
+	// ContainerHeaderSize is the size of the header of a container.
+	//
+	// Since 0.1.15
+	ContainerHeaderSize = 24
+)
+
+var (
+	// ContainerMagic is the leading bytes of a container.
+	//
+	// Since 0.1.15
+	ContainerMagic = [4]byte{'S', 'L', 'I', 'M'}
+
+	castagnoli = crc32.MakeTable(crc32.Castagnoli)
+)
+
+var (
+	// InvalidMagic is returned if data does not start with ContainerMagic.
+	InvalidMagic = errors.New("invalid container magic")
+
+	// UnsupportedVersion is returned if the container format version is newer
+	// than ContainerVersion.
+	UnsupportedVersion = errors.New("unsupported container version")
+
+	// TypeMismatch is returned if a container stores a different structure
+	// from the one to read into.
+	TypeMismatch = errors.New("container type mismatch")
+
+	// ChecksumMismatch is returned if the header or the body of a container is
+	// corrupted.
+	ChecksumMismatch = errors.New("container checksum mismatch")
+)
+
+// ContainerType identifies the structure stored in a container.
+//
+// Since 0.1.15
+type ContainerType uint16
+
+// Types of structures could be stored in a container.
+// A new type is only appended, thus the value of a type never changes.
+const (
+	TypeSlimArray ContainerType = iota + 1
+	TypeSlimBytes
+	TypeSlimMap
+	TypeSortedBytes
+	TypeCompressedBytes
+	TypeSlimF64
+	TypeSlimTimes
+	TypeIPSet
+	TypeSlimGraph
+	TypeSlimTable
+)
+
+const (
+	// FlagU64 is set if a SlimArray stores uint64 elements.
+	//
+	// Since 0.1.15
+	FlagU64 uint32 = 1 << iota
+
+	// FlagLossy is set if a SlimArray or a SlimF64 stores elements with
+	// bounded error.
+	//
+	// Since 0.1.15
+	FlagLossy
+)
+
+// ContainerHeader is the decoded header of a container.
+//
+// A container wraps a protobuf serialized structure with a header and
+// checksums, so that a blob is self-describing and corruption is detected.
+//
+// All integers are big endian:
+//
+//	header:
+//	    Magic      [4]byte  "SLIM"
+//	    Version    uint16
+//	    Type       uint16   ContainerType
+//	    Flags      uint32   type specific options, e.g., FlagU64
+//	    BodySize   uint64
+//	    HeaderCRC  uint32   CRC32C of the above fields
+//	body:
+//	    Body       [BodySize]byte   protobuf serialized structure
+//	    BodyCRC    uint32           CRC32C of Body
+//
+// Since 0.1.15
+type ContainerHeader struct {
+	Version  uint16
+	Type     ContainerType
+	Flags    uint32
+	BodySize uint64
+}
+
+// ParseHeader decodes the container header from the leading
+// ContainerHeaderSize bytes of buf, to find out the structure stored in a
+// container before reading it.
+//
+// It returns io.EOF if buf is empty, io.ErrUnexpectedEOF if buf is too short,
+// or InvalidMagic, UnsupportedVersion or ChecksumMismatch if the header is
+// invalid.
+//
+// Since 0.1.15
+func ParseHeader(buf []byte) (*ContainerHeader, error) {
+	h, _, err := readHeader(bytes.NewReader(buf))
+	return h, err
+}
+
+// readHeader reads and validates a container header.
+// It returns the header and the number of bytes read.
+func readHeader(r io.Reader) (*ContainerHeader, int64, error) {
+
+	buf := make([]byte, ContainerHeaderSize)
+
+	// Read magic and version first: a newer version may have a different
+	// header layout.
+	// io.EOF is returned as is if there is no more container in r.
+	n, err := io.ReadFull(r, buf[:6])
+	if err != nil {
+		return nil, int64(n), err
+	}
+
+	if !bytes.Equal(buf[:4], ContainerMagic[:]) {
+		return nil, int64(n), InvalidMagic
+	}
+
+	ver := binary.BigEndian.Uint16(buf[4:6])
+	if ver == 0 || ver > ContainerVersion {
+		return nil, int64(n), fmt.Errorf("%w: %d, latest supported: %d", UnsupportedVersion, ver, ContainerVersion)
+	}
+
+	m, err := io.ReadFull(r, buf[6:])
+	n += m
+	if err != nil {
+		return nil, int64(n), eofToUnexpected(err)
+	}
+
+	crc := binary.BigEndian.Uint32(buf[20:24])
+	if crc32.Checksum(buf[:20], castagnoli) != crc {
+		return nil, int64(n), fmt.Errorf("%w: header", ChecksumMismatch)
+	}
+
+	h := &ContainerHeader{
+		Version:  ver,
+		Type:     ContainerType(binary.BigEndian.Uint16(buf[6:8])),
+		Flags:    binary.BigEndian.Uint32(buf[8:12]),
+		BodySize: binary.BigEndian.Uint64(buf[12:20]),
+	}
+	return h, int64(n), nil
+}
+
+// writeContainer writes msg into w in container format.
+func writeContainer(w io.Writer, typ ContainerType, flags uint32, msg proto.Message) (int64, error) {
+
+	body, err := proto.Marshal(msg)
+	if err != nil {
+		return 0, err
+	}
+
+	buf := make([]byte, ContainerHeaderSize, ContainerHeaderSize+len(body)+4)
+	copy(buf, ContainerMagic[:])
+	binary.BigEndian.PutUint16(buf[4:6], ContainerVersion)
+	binary.BigEndian.PutUint16(buf[6:8], uint16(typ))
+	binary.BigEndian.PutUint32(buf[8:12], flags)
+	binary.BigEndian.PutUint64(buf[12:20], uint64(len(body)))
+	binary.BigEndian.PutUint32(buf[20:24], crc32.Checksum(buf[:20], castagnoli))
+
+	buf = append(buf, body...)
+
+	crc := make([]byte, 4)
+	binary.BigEndian.PutUint32(crc, crc32.Checksum(body, castagnoli))
+	buf = append(buf, crc...)
+
+	n, err := w.Write(buf)
+	return int64(n), err
+}
+
+// readContainer reads exactly one container of type typ from r into msg.
+func readContainer(r io.Reader, typ ContainerType, msg proto.Message) (int64, error) {
+
+	h, n, err := readHeader(r)
+	if err != nil {
+		return n, err
+	}
+
+	if h.BodySize > math.MaxInt64-4 {
+		return n, io.ErrUnexpectedEOF
+	}
+
+	if h.Type != typ {
+		return n, fmt.Errorf("%w: expect %d, got %d", TypeMismatch, typ, h.Type)
+	}
+
+	// Do not allocate BodySize bytes upfront: it may be large on a truncated
+	// stream.
+	body, err := ioutil.ReadAll(io.LimitReader(r, int64(h.BodySize)+4))
+	n += int64(len(body))
+	if err != nil {
+		return n, err
+	}
+	if uint64(len(body)) != h.BodySize+4 {
+		return n, io.ErrUnexpectedEOF
+	}
+
+	crc := binary.BigEndian.Uint32(body[h.BodySize:])
+	body = body[:h.BodySize]
+	if crc32.Checksum(body, castagnoli) != crc {
+		return n, fmt.Errorf("%w: body", ChecksumMismatch)
+	}
+
+	return n, proto.Unmarshal(body, msg)
+}
+
+func eofToUnexpected(err error) error {
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
+
+// WriteTo writes the SlimArray into w in container format.
+// It implements io.WriterTo.
+//
+// Since 0.1.15
+func (sm *SlimArray) WriteTo(w io.Writer) (int64, error) {
+	flags := uint32(0)
+	if sm.EltWidth == 64 {
+		flags |= FlagU64
+	}
+	if sm.MaxErr > 0 {
+		flags |= FlagLossy
+	}
+	return writeContainer(w, TypeSlimArray, flags, sm)
+}
+
+// ReadFrom reads a SlimArray in container format from r.
+// It implements io.ReaderFrom, but reads exactly one container from r, thus
+// several containers could be read one by one from a stream.
+//
+// It returns InvalidMagic, UnsupportedVersion, TypeMismatch or
+// ChecksumMismatch if the container is invalid, io.ErrUnexpectedEOF if it is
+// truncated, or io.EOF if there is no more data in r.
+//
+// Since 0.1.15
+func (sm *SlimArray) ReadFrom(r io.Reader) (int64, error) {
+	return readContainer(r, TypeSlimArray, sm)
+}
+
+// WriteTo writes the SlimBytes into w in container format.
+//
+// Since 0.1.15
+func (b *SlimBytes) WriteTo(w io.Writer) (int64, error) {
+	return writeContainer(w, TypeSlimBytes, 0, b)
+}
+
+// ReadFrom reads a SlimBytes in container format from r.
+//
+// Since 0.1.15
+func (b *SlimBytes) ReadFrom(r io.Reader) (int64, error) {
+	return readContainer(r, TypeSlimBytes, b)
+}
+
+// WriteTo writes the SlimMap into w in container format.
+//
+// Since 0.1.15
+func (m *SlimMap) WriteTo(w io.Writer) (int64, error) {
+	return writeContainer(w, TypeSlimMap, 0, m)
+}
+
+// ReadFrom reads a SlimMap in container format from r.
+//
+// Since 0.1.15
+func (m *SlimMap) ReadFrom(r io.Reader) (int64, error) {
+	return readContainer(r, TypeSlimMap, m)
+}
+
+// WriteTo writes the SortedBytes into w in container format.
+//
+// Since 0.1.15
+func (sb *SortedBytes) WriteTo(w io.Writer) (int64, error) {
+	return writeContainer(w, TypeSortedBytes, 0, sb)
+}
+
+// ReadFrom reads a SortedBytes in container format from r.
+//
+// Since 0.1.15
+func (sb *SortedBytes) ReadFrom(r io.Reader) (int64, error) {
+	return readContainer(r, TypeSortedBytes, sb)
+}
+
+// WriteTo writes the CompressedBytes into w in container format.
+//
+// Since 0.1.15
+func (cb *CompressedBytes) WriteTo(w io.Writer) (int64, error) {
+	return writeContainer(w, TypeCompressedBytes, 0, cb)
+}
+
+// ReadFrom reads a CompressedBytes in container format from r.
+//
+// Since 0.1.15
+func (cb *CompressedBytes) ReadFrom(r io.Reader) (int64, error) {
+	return readContainer(r, TypeCompressedBytes, cb)
+}
+
+// WriteTo writes the SlimF64 into w in container format.
+// FlagLossy is set if it is built with a MaxErr.
+//
+// Since 0.1.15
+func (f *SlimF64) WriteTo(w io.Writer) (int64, error) {
+	flags := uint32(0)
+	if f.MaxErr > 0 {
+		flags |= FlagLossy
+	}
+	return writeContainer(w, TypeSlimF64, flags, f)
+}
+
+// ReadFrom reads a SlimF64 in container format from r.
+//
+// Since 0.1.15
+func (f *SlimF64) ReadFrom(r io.Reader) (int64, error) {
+	return readContainer(r, TypeSlimF64, f)
+}
+
+// WriteTo writes the SlimTimes into w in container format.
+//
+// Since 0.1.15
+func (st *SlimTimes) WriteTo(w io.Writer) (int64, error) {
+	return writeContainer(w, TypeSlimTimes, 0, st)
+}
+
+// ReadFrom reads a SlimTimes in container format from r.
+//
+// Since 0.1.15
+func (st *SlimTimes) ReadFrom(r io.Reader) (int64, error) {
+	return readContainer(r, TypeSlimTimes, st)
+}
+
+// WriteTo writes the IPSet into w in container format.
+//
+// Since 0.1.15
+func (s *IPSet) WriteTo(w io.Writer) (int64, error) {
+	return writeContainer(w, TypeIPSet, 0, s)
+}
+
+// ReadFrom reads an IPSet in container format from r.
+//
+// Since 0.1.15
+func (s *IPSet) ReadFrom(r io.Reader) (int64, error) {
+	return readContainer(r, TypeIPSet, s)
+}
+
+// WriteTo writes the SlimGraph into w in container format.
+//
+// Since 0.1.15
+func (g *SlimGraph) WriteTo(w io.Writer) (int64, error) {
+	return writeContainer(w, TypeSlimGraph, 0, g)
+}
+
+// ReadFrom reads a SlimGraph in container format from r.
+//
+// Since 0.1.15
+func (g *SlimGraph) ReadFrom(r io.Reader) (int64, error) {
+	return readContainer(r, TypeSlimGraph, g)
+}
+
+// WriteTo writes the SlimTable into w in container format.
+//
+// Since 0.1.15
+func (tb *SlimTable) WriteTo(w io.Writer) (int64, error) {
+	return writeContainer(w, TypeSlimTable, 0, tb)
+}
+
+// ReadFrom reads a SlimTable in container format from r.
+//
+// Since 0.1.15
+func (tb *SlimTable) ReadFrom(r io.Reader) (int64, error) {
+	return readContainer(r, TypeSlimTable, tb)
+}
@@ -0,0 +1,185 @@
+package slimarray
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/stretchr/testify/require"
+)
+
+type container interface {
+	proto.Message
+	io.WriterTo
+	io.ReaderFrom
+}
+
+func TestContainer_writeRead(t *testing.T) {
+
+	ta := require.New(t)
+
+	sb, _ := NewBytes([][]byte{[]byte("a"), []byte("bc")})
+	m, _ := NewMap([][]byte{[]byte("k")}, [][]byte{[]byte("v")})
+	sorted, _ := NewSortedBytes([][]byte{[]byte("a"), []byte("ab")}, 0)
+	cb, _ := NewCompressedBytes([][]byte{[]byte("abc"), []byte("abd")}, nil)
+	f, _ := NewF64([]float64{1.5, 2.5}, nil)
+	times, _ := NewTimes([]time.Time{time.Unix(1, 0), time.Unix(2, 0)}, nil)
+	ips, _ := NewIPSetFromCIDRs([]string{"10.0.0.0/8", "fe80::/10"})
+	g, _ := NewGraph(3, []Edge{{0, 1}, {1, 2}})
+	tbb, _ := NewTableBuilder(testSchema)
+	tbb.Add(uint32(1), int64(2), "x")
+	tb, _ := tbb.Build(nil)
+
+	cases := []struct {
+		c     container
+		empty container
+		typ   ContainerType
+		flags uint32
+	}{
+		{NewU32(testNums), &SlimArray{}, TypeSlimArray, 0},
+		{NewU64([]uint64{1, 1 << 40}), &SlimArray{}, TypeSlimArray, FlagU64},
+		{NewU32Lossy(testNums, 3), &SlimArray{}, TypeSlimArray, FlagLossy},
+		{sb, &SlimBytes{}, TypeSlimBytes, 0},
+		{m, &SlimMap{}, TypeSlimMap, 0},
+		{sorted, &SortedBytes{}, TypeSortedBytes, 0},
+		{cb, &CompressedBytes{}, TypeCompressedBytes, 0},
+		{f, &SlimF64{}, TypeSlimF64, 0},
+		{times, &SlimTimes{}, TypeSlimTimes, 0},
+		{ips, &IPSet{}, TypeIPSet, 0},
+		{g, &SlimGraph{}, TypeSlimGraph, 0},
+		{tb, &SlimTable{}, TypeSlimTable, 0},
+	}
+
+	for i, c := range cases {
+
+		buf := &bytes.Buffer{}
+		n, err := c.c.WriteTo(buf)
+		ta.NoError(err, "%d-th case", i+1)
+		ta.Equal(int64(buf.Len()), n, "%d-th case", i+1)
+		ta.Equal(int64(ContainerHeaderSize+proto.Size(c.c)+4), n, "%d-th case", i+1)
+
+		h, err := ParseHeader(buf.Bytes())
+		ta.NoError(err, "%d-th case", i+1)
+		ta.Equal(&ContainerHeader{
+			Version:  ContainerVersion,
+			Type:     c.typ,
+			Flags:    c.flags,
+			BodySize: uint64(proto.Size(c.c)),
+		}, h, "%d-th case", i+1)
+
+		n, err = c.empty.ReadFrom(buf)
+		ta.NoError(err, "%d-th case", i+1)
+		ta.Equal(int64(ContainerHeaderSize+proto.Size(c.c)+4), n, "%d-th case", i+1)
+		ta.True(proto.Equal(c.c, c.empty), "%d-th case", i+1)
+	}
+}
+
+func TestContainer_stream(t *testing.T) {
+
+	ta := require.New(t)
+
+	a := NewU32([]uint32{1, 2, 3})
+	b := NewU32([]uint32{4, 5})
+
+	buf := &bytes.Buffer{}
+	_, err := a.WriteTo(buf)
+	ta.NoError(err)
+	_, err = b.WriteTo(buf)
+	ta.NoError(err)
+
+	got := &SlimArray{}
+
+	_, err = got.ReadFrom(buf)
+	ta.NoError(err)
+	ta.Equal(uint32(3), got.Get(2))
+	ta.Equal(3, got.Len())
+
+	_, err = got.ReadFrom(buf)
+	ta.NoError(err)
+	ta.Equal(uint32(5), got.Get(1))
+	ta.Equal(2, got.Len())
+
+	_, err = got.ReadFrom(buf)
+	ta.Equal(io.EOF, err)
+}
+
+func TestContainer_error(t *testing.T) {
+
+	ta := require.New(t)
+
+	buf := &bytes.Buffer{}
+	_, err := NewU32(testNums).WriteTo(buf)
+	ta.NoError(err)
+	data := buf.Bytes()
+
+	read := func(b []byte) error {
+		_, err := (&SlimArray{}).ReadFrom(bytes.NewReader(b))
+		return err
+	}
+
+	modify := func(f func(b []byte)) []byte {
+		b := append([]byte{}, data...)
+		f(b)
+		return b
+	}
+
+	ta.NoError(read(data))
+
+	ta.Equal(io.EOF, read(nil))
+
+	// truncated
+	for _, l := range []int{1, 3, 6, ContainerHeaderSize - 1, ContainerHeaderSize, len(data) - 1} {
+		ta.Equal(io.ErrUnexpectedEOF, read(data[:l]), "len: %d", l)
+	}
+
+	ta.Equal(InvalidMagic, read(modify(func(b []byte) { b[0] = 'X' })))
+
+	// unknown versions are rejected before checking the header checksum
+	for _, ver := range []uint16{0, ContainerVersion + 1, 0xffff} {
+		err = read(modify(func(b []byte) { binary.BigEndian.PutUint16(b[4:], ver) }))
+		ta.True(errors.Is(err, UnsupportedVersion), "version: %d", ver)
+	}
+	ta.Contains(err.Error(), "65535")
+
+	// corrupted header
+	for _, i := range []int{6, 8, 12, 19, 20} {
+		err = read(modify(func(b []byte) { b[i] ^= 1 }))
+		ta.True(errors.Is(err, ChecksumMismatch), "i: %d", i)
+	}
+
+	// corrupted body
+	for _, i := range []int{ContainerHeaderSize, len(data) / 2, len(data) - 1} {
+		err = read(modify(func(b []byte) { b[i] ^= 1 }))
+		ta.True(errors.Is(err, ChecksumMismatch), "i: %d", i)
+	}
+
+	// type mismatch
+	_, err = (&SlimBytes{}).ReadFrom(bytes.NewReader(data))
+	ta.True(errors.Is(err, TypeMismatch))
+
+	_, err = ParseHeader(data[:ContainerHeaderSize-1])
+	ta.Equal(io.ErrUnexpectedEOF, err)
+}
+
+func TestContainer_hugeBodySize(t *testing.T) {
+
+	ta := require.New(t)
+
+	buf := &bytes.Buffer{}
+	_, err := NewU32(testNums).WriteTo(buf)
+	ta.NoError(err)
+
+	for _, size := range []uint64{1 << 40, 1<<64 - 1} {
+		b := append([]byte{}, buf.Bytes()...)
+		binary.BigEndian.PutUint64(b[12:20], size)
+		binary.BigEndian.PutUint32(b[20:24], crc32.Checksum(b[:20], castagnoli))
+
+		_, err = (&SlimArray{}).ReadFrom(bytes.NewReader(b))
+		ta.Equal(io.ErrUnexpectedEOF, err, "size: %d", size)
+	}
+}
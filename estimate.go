@@ -0,0 +1,66 @@
+package slimarray
+
+import (
+	"github.com/openacid/low/size"
+)
+
+// EstimateSize estimates the memory cost in bytes of a SlimArray created by
+// NewU32(nums), without building it.
+//
+// It builds only a sample of segments, sampleRate of all segments evenly
+// distributed in nums, and extrapolates the memory cost of all segments.
+// The cost of EstimateSize is roughly sampleRate * the cost of NewU32.
+// A sampleRate >= 1 builds every segment and the result is exact.
+//
+// The error depends on how uniform the data is.
+// For data with a similar distribution in every segment, such as a sorted
+// array of random numbers, the error is less than 5% with sampleRate=0.1 and
+// more than 100 segments(100*1024 elts).
+// At least one segment is sampled, thus for a small array the error could be
+// large if the segments are not alike.
+//
+// Since 0.1.15
+func EstimateSize(nums []uint32, sampleRate float64) int64 {
+
+	n := int64(len(nums))
+
+	// the fixed cost of an empty SlimArray and the trailing residual word.
+	fixed := int64(size.Of(&SlimArray{})) + 8
+
+	if n == 0 {
+		return fixed
+	}
+
+	segCnt := (n + segSize - 1) / segSize
+
+	sampleCnt := int64(float64(segCnt)*sampleRate + 0.5)
+	if sampleCnt < 1 {
+		sampleCnt = 1
+	}
+	if sampleCnt > segCnt {
+		sampleCnt = segCnt
+	}
+
+	sampledElts := int64(0)
+	sampledBytes := int64(0)
+
+	for i := int64(0); i < sampleCnt; i++ {
+
+		segIdx := i * segCnt / sampleCnt
+
+		s := segIdx * segSize
+		e := s + segSize
+		if e > n {
+			e = n
+		}
+
+		_, polynomials, configs, words := newSeg(nums[s:e], 0, (*Opt)(nil).withDefault())
+
+		// bitmap and rank of a segment.
+		sampledBytes += 8 + 8
+		sampledBytes += int64(len(polynomials)+len(configs)+len(words)) * 8
+		sampledElts += e - s
+	}
+
+	return fixed + sampledBytes*n/sampledElts
+}
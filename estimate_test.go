@@ -0,0 +1,84 @@
+package slimarray
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/openacid/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEstimateSize_exact(t *testing.T) {
+
+	ta := require.New(t)
+
+	cases := [][]uint32{
+		{},
+		{1},
+		testNums,
+		testutil.RandU32Slice(0, 1024*10+3, 100),
+	}
+
+	for i, nums := range cases {
+		want := NewU32(nums).Stats().MemTotal
+		ta.Equal(want, EstimateSize(nums, 1), "%d-th", i+1)
+		ta.Equal(want, EstimateSize(nums, 2), "%d-th", i+1)
+	}
+}
+
+func TestEstimateSize_errorBound(t *testing.T) {
+
+	ta := require.New(t)
+
+	n := 1024 * 200
+	rnd := rand.New(rand.NewSource(int64(n)))
+
+	sortedRand := func(rng float64) []uint32 {
+		nums := make([]uint32, n)
+		for i := range nums {
+			nums[i] = uint32(rnd.Float64() * rng)
+		}
+		sort.Slice(nums, func(i, j int) bool { return nums[i] < nums[j] })
+		return nums
+	}
+
+	cases := []struct {
+		name string
+		nums []uint32
+	}{
+		{"step=64", testutil.RandU32Slice(0, int32(n), 64)},
+		{"step=1024", testutil.RandU32Slice(1<<20, int32(n), 1024)},
+		{"sorted,rng=n", sortedRand(float64(n))},
+		{"sorted,rng=1e9", sortedRand(1e9)},
+	}
+
+	for _, c := range cases {
+		want := NewU32(c.nums).Stats().MemTotal
+
+		for _, rate := range []float64{0.1, 0.3} {
+			got := EstimateSize(c.nums, rate)
+			errRate := float64(got-want) / float64(want)
+			fmt.Printf("%s: rate: %.1f, want: %d, got: %d, error: %.2f%%\n",
+				c.name, rate, want, got, errRate*100)
+
+			ta.InDelta(0, errRate, 0.05, "%s: rate: %.1f", c.name, rate)
+		}
+	}
+}
+
+func BenchmarkEstimateSize(b *testing.B) {
+
+	n := int32(1024 * 10)
+	ns := testutil.RandU32Slice(0, n, 128)
+
+	s := int64(0)
+
+	b.ResetTimer()
+	for i := 0; i < b.N/int(n)+1; i++ {
+		s += EstimateSize(ns, 0.1)
+	}
+
+	Output = int(s)
+}
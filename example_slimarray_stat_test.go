@@ -54,18 +54,18 @@ func ExampleSlimArray_Stat() {
 	// n=1000 rng=[0, 1000]:
 	//
 	//            n: 1000
-	//    mem_total: 856
+	//    mem_total: 832
 	//     bits/elt: 6
 	//
 	// n=1000000 rng=[0, 1000000]:
 	//
 	//            n: 1000000
-	//    mem_total: 705720
+	//    mem_total: 676496
 	//     bits/elt: 5
 	//
 	// n=1000000 rng=[0, 1000000000]:
 	//
 	//            n: 1000000
-	//    mem_total: 2078336
+	//    mem_total: 2047080
 	//     bits/elt: 16
 }
@@ -2,6 +2,7 @@ package slimarray_test
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/openacid/slimarray"
 )
@@ -38,3 +39,22 @@ func ExampleSlimBytes() {
 	// Output:
 	// SlimBytes is an array of var-length records(a record is a []byte which is indexed by SlimArray
 }
+
+func ExampleNewBytesFromLines() {
+
+	text := "foo\nbar\n\nhello\r\nworld"
+
+	a, err := slimarray.NewBytesFromLines(strings.NewReader(text))
+	_ = err
+
+	for i := 0; i < a.Positions.Len()-1; i++ {
+		fmt.Printf("%q\n", a.Get(int32(i)))
+	}
+
+	// Output:
+	// "foo"
+	// "bar"
+	// ""
+	// "hello"
+	// "world"
+}
@@ -0,0 +1,29 @@
+package slimarray_test
+
+import (
+	"fmt"
+
+	"github.com/openacid/slimarray"
+)
+
+func ExampleNewF64() {
+
+	nums := []float64{20.1, 20.15, 20.3, 20.42, 20.5, 20.71, 20.8, 20.93}
+
+	lossless, _ := slimarray.NewF64(nums, nil)
+	fmt.Println(lossless.Get(3))
+
+	lossy, _ := slimarray.NewF64(nums, &slimarray.F64Opt{MaxErr: 0.05})
+
+	rst := make([]float64, 3)
+	lossy.Slice(2, 5, rst)
+	for _, x := range rst {
+		fmt.Printf("%.2f\n", x)
+	}
+
+	// Output:
+	// 20.42
+	// 20.30
+	// 20.40
+	// 20.50
+}
@@ -0,0 +1,39 @@
+package slimarray_test
+
+import (
+	"fmt"
+
+	"github.com/openacid/slimarray"
+)
+
+func ExampleSlimMap() {
+
+	keys := [][]byte{
+		[]byte("apple"),
+		[]byte("banana"),
+		[]byte("apricot"),
+		[]byte("cherry"),
+	}
+	values := [][]byte{
+		[]byte("red"),
+		[]byte("yellow"),
+		[]byte("orange"),
+		[]byte("dark red"),
+	}
+
+	m, err := slimarray.NewMap(keys, values)
+	_ = err
+
+	v, found := m.Get([]byte("banana"))
+	fmt.Println(string(v), found)
+
+	m.Prefix([]byte("ap"), func(k, v []byte) bool {
+		fmt.Println(string(k), string(v))
+		return true
+	})
+
+	// Output:
+	// yellow true
+	// apple red
+	// apricot orange
+}
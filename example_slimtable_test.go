@@ -0,0 +1,35 @@
+package slimarray_test
+
+import (
+	"fmt"
+
+	"github.com/openacid/slimarray"
+)
+
+func ExampleNewTableBuilder() {
+
+	b, _ := slimarray.NewTableBuilder([]slimarray.ColumnDef{
+		{Name: "port", Type: slimarray.ColumnU32},
+		{Name: "since", Type: slimarray.ColumnI64},
+		{Name: "service", Type: slimarray.ColumnBytes},
+	})
+
+	b.Add(uint32(443), int64(1995), "https")
+	b.Add(uint32(22), int64(1995), "ssh")
+	b.Add(uint32(80), int64(1991), "http")
+
+	tb, _ := b.Build(&slimarray.TableOpt{SortBy: "port"})
+
+	i, found := tb.Lookup(uint32(80))
+	fmt.Println(found, tb.I64(1, i), string(tb.Bytes(2, i)))
+
+	for i := int32(0); i < int32(tb.Len()); i++ {
+		fmt.Printf("%v %v %s\n", tb.Row(i)...)
+	}
+
+	// Output:
+	// true 1991 http
+	// 22 1995 ssh
+	// 80 1991 http
+	// 443 1995 https
+}
@@ -4,4 +4,4 @@ package slimarray
 // Usage:
 //     go generate ./...
 
-//go:generate protoc --proto_path=. --go_out=. slimarray.proto
+//go:generate protoc --proto_path=. --go_out=paths=source_relative:. slimarray.proto
@@ -0,0 +1,7 @@
+package index
+
+// Auto-generated code definition
+// Usage:
+//     go generate ./...
+
+//go:generate protoc --proto_path=.. --go_out=paths=source_relative:.. index/index.proto
@@ -0,0 +1,252 @@
+// Package index is an inverted index built on slimarray.
+//
+// An Index maps a term to a posting list: the sorted ids of documents that
+// contain the term and the frequency of the term in every document.
+// Terms are stored in a front-coded slimarray.SortedBytes, and posting lists
+// of all terms are packed together in slimarray.SlimArray.
+//
+// A posting list is iterated with Postings, which skips to a document with
+// galloping search. And() and Or() queries documents that contain all or any
+// of terms.
+//
+// An Index is a protobuf message, thus it is serialized with proto.Marshal().
+package index
+
+import (
+	"sort"
+
+	"github.com/openacid/slimarray"
+)
+
+// Builder collects postings to build an Index.
+//
+// Since 0.1.15
+type Builder struct {
+	// term -> doc -> frequency
+	postings map[string]map[uint32]uint32
+}
+
+// NewBuilder creates an empty Builder.
+//
+// Since 0.1.15
+func NewBuilder() *Builder {
+	return &Builder{
+		postings: map[string]map[uint32]uint32{},
+	}
+}
+
+// Add adds an occurrence of term in document doc.
+// Adding the same term and doc again increases the term frequency.
+//
+// Since 0.1.15
+func (b *Builder) Add(term string, doc uint32) {
+	docs := b.postings[term]
+	if docs == nil {
+		docs = map[uint32]uint32{}
+		b.postings[term] = docs
+	}
+	docs[doc]++
+}
+
+// AddDoc adds every term in document doc.
+//
+// Since 0.1.15
+func (b *Builder) AddDoc(doc uint32, terms []string) {
+	for _, t := range terms {
+		b.Add(t, doc)
+	}
+}
+
+// Build creates an Index from all added postings.
+// It returns slimarray.TooManyRows if there are more than 2^31-1 terms or
+// postings.
+//
+// Since 0.1.15
+func (b *Builder) Build() (*Index, error) {
+
+	terms := make([]string, 0, len(b.postings))
+	for t := range b.postings {
+		terms = append(terms, t)
+	}
+	sort.Strings(terms)
+
+	n := int64(0)
+	for _, docs := range b.postings {
+		n += int64(len(docs))
+	}
+	if n > 0x7fffffff || len(terms) > 0x7fffffff-1 {
+		return nil, slimarray.TooManyRows
+	}
+
+	records := make([][]byte, len(terms))
+	offsets := make([]uint32, 0, len(terms)+1)
+	docs := make([]uint32, 0, n)
+	freqs := make([]uint32, 0, n)
+
+	for i, t := range terms {
+		records[i] = []byte(t)
+		offsets = append(offsets, uint32(len(docs)))
+
+		s := len(docs)
+		for d := range b.postings[t] {
+			docs = append(docs, d)
+		}
+		ds := docs[s:]
+		sort.Slice(ds, func(i, j int) bool { return ds[i] < ds[j] })
+
+		for _, d := range ds {
+			freqs = append(freqs, b.postings[t][d])
+		}
+	}
+	offsets = append(offsets, uint32(len(docs)))
+
+	sb, err := slimarray.NewSortedBytes(records, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	idx := &Index{
+		Terms:   sb,
+		Offsets: slimarray.NewU32(offsets),
+		Docs:    slimarray.NewU32(docs),
+		Freqs:   slimarray.NewU32(freqs),
+	}
+	return idx, nil
+}
+
+// Len returns the number of terms.
+//
+// Since 0.1.15
+func (idx *Index) Len() int {
+	return idx.Terms.Len()
+}
+
+// Term returns the i-th term in sorted order.
+//
+// Since 0.1.15
+func (idx *Index) Term(i int32) string {
+	return string(idx.Terms.Get(i))
+}
+
+// Postings returns an iterator of the posting list of term.
+// The posting list is empty if term is not in the index.
+//
+// Since 0.1.15
+func (idx *Index) Postings(term string) *Postings {
+
+	p := &Postings{idx: idx}
+
+	key := []byte(term)
+	i := idx.Terms.Search(key)
+	if int(i) < idx.Len() && string(idx.Terms.Get(i)) == term {
+		s, e := idx.Offsets.Get2(i)
+		p.start, p.end = int32(s), int32(e)
+	}
+
+	p.i = p.start - 1
+	return p
+}
+
+// DocFreq returns the number of documents that contain term.
+//
+// Since 0.1.15
+func (idx *Index) DocFreq(term string) int {
+	return idx.Postings(term).Len()
+}
+
+// And returns the sorted ids of documents that contain all of terms.
+//
+// It iterates the shortest posting list, and skips to the current document in
+// other posting lists with galloping search. Thus it costs
+// O(m * log(n/m)), where m and n are the length of the shortest and the
+// longest posting lists.
+//
+// Since 0.1.15
+func (idx *Index) And(terms ...string) []uint32 {
+
+	rst := []uint32{}
+
+	if len(terms) == 0 {
+		return rst
+	}
+
+	ps := make([]*Postings, len(terms))
+	for i, t := range terms {
+		ps[i] = idx.Postings(t)
+	}
+	sort.Slice(ps, func(i, j int) bool { return ps[i].Len() < ps[j].Len() })
+
+	lead, others := ps[0], ps[1:]
+
+	if !lead.Next() {
+		return rst
+	}
+
+	doc := lead.Doc()
+	for {
+		matched := true
+
+		for _, p := range others {
+			if !p.Advance(doc) {
+				return rst
+			}
+			if p.Doc() > doc {
+				// Restart with the greater doc.
+				if !lead.Advance(p.Doc()) {
+					return rst
+				}
+				doc = lead.Doc()
+				matched = false
+				break
+			}
+		}
+
+		if matched {
+			rst = append(rst, doc)
+			if !lead.Next() {
+				return rst
+			}
+			doc = lead.Doc()
+		}
+	}
+}
+
+// Or returns the sorted ids of documents that contain any of terms.
+//
+// Since 0.1.15
+func (idx *Index) Or(terms ...string) []uint32 {
+
+	rst := []uint32{}
+
+	for _, t := range terms {
+		rst = union(rst, idx.Postings(t).Docs())
+	}
+
+	return rst
+}
+
+// union merges two sorted lists of distinct docs.
+func union(a, b []uint32) []uint32 {
+
+	rst := make([]uint32, 0, len(a)+len(b))
+
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] < b[j]:
+			rst = append(rst, a[i])
+			i++
+		case a[i] > b[j]:
+			rst = append(rst, b[j])
+			j++
+		default:
+			rst = append(rst, a[i])
+			i++
+			j++
+		}
+	}
+
+	rst = append(rst, a[i:]...)
+	rst = append(rst, b[j:]...)
+	return rst
+}
@@ -0,0 +1,201 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.25.0
+// 	protoc        v3.7.1
+// source: index/index.proto
+
+package index
+
+import (
+	proto "github.com/golang/protobuf/proto"
+	slimarray "github.com/openacid/slimarray"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// This is a compile-time assertion that a sufficiently up-to-date version
+// of the legacy proto package is being used.
+const _ = proto.ProtoPackageIsVersion4
+
+// Index is an inverted index that maps a term to a posting list: the sorted
+// ids of documents that contain the term, and the frequency of the term in
+// every document.
+//
+// Posting lists of all terms are packed together in the order of terms.
+//
+// Since 0.1.15
+type Index struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Terms is the sorted terms.
+	Terms *slimarray.SortedBytes `protobuf:"bytes,20,opt,name=Terms,proto3" json:"Terms,omitempty"`
+	// Offsets[i] is the index of the first posting of the i-th term in Docs
+	// and Freqs.
+	// There are n + 1 uint32 in it. The last one is the number of postings.
+	Offsets *slimarray.SlimArray `protobuf:"bytes,21,opt,name=Offsets,proto3" json:"Offsets,omitempty"`
+	// Docs is the sorted document ids of every term.
+	Docs *slimarray.SlimArray `protobuf:"bytes,22,opt,name=Docs,proto3" json:"Docs,omitempty"`
+	// Freqs[j] is the frequency of a term in document Docs[j].
+	Freqs *slimarray.SlimArray `protobuf:"bytes,23,opt,name=Freqs,proto3" json:"Freqs,omitempty"`
+}
+
+func (x *Index) Reset() {
+	*x = Index{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_index_index_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Index) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Index) ProtoMessage() {}
+
+func (x *Index) ProtoReflect() protoreflect.Message {
+	mi := &file_index_index_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Index.ProtoReflect.Descriptor instead.
+func (*Index) Descriptor() ([]byte, []int) {
+	return file_index_index_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Index) GetTerms() *slimarray.SortedBytes {
+	if x != nil {
+		return x.Terms
+	}
+	return nil
+}
+
+func (x *Index) GetOffsets() *slimarray.SlimArray {
+	if x != nil {
+		return x.Offsets
+	}
+	return nil
+}
+
+func (x *Index) GetDocs() *slimarray.SlimArray {
+	if x != nil {
+		return x.Docs
+	}
+	return nil
+}
+
+func (x *Index) GetFreqs() *slimarray.SlimArray {
+	if x != nil {
+		return x.Freqs
+	}
+	return nil
+}
+
+var File_index_index_proto protoreflect.FileDescriptor
+
+var file_index_index_proto_rawDesc = []byte{
+	0x0a, 0x11, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x2f, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x12, 0x0f, 0x73, 0x6c, 0x69, 0x6d, 0x61, 0x72, 0x72, 0x61, 0x79, 0x2e, 0x69,
+	0x6e, 0x64, 0x65, 0x78, 0x1a, 0x0f, 0x73, 0x6c, 0x69, 0x6d, 0x61, 0x72, 0x72, 0x61, 0x79, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x93, 0x01, 0x0a, 0x05, 0x49, 0x6e, 0x64, 0x65, 0x78, 0x12,
+	0x22, 0x0a, 0x05, 0x54, 0x65, 0x72, 0x6d, 0x73, 0x18, 0x14, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0c,
+	0x2e, 0x53, 0x6f, 0x72, 0x74, 0x65, 0x64, 0x42, 0x79, 0x74, 0x65, 0x73, 0x52, 0x05, 0x54, 0x65,
+	0x72, 0x6d, 0x73, 0x12, 0x24, 0x0a, 0x07, 0x4f, 0x66, 0x66, 0x73, 0x65, 0x74, 0x73, 0x18, 0x15,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x0a, 0x2e, 0x53, 0x6c, 0x69, 0x6d, 0x41, 0x72, 0x72, 0x61, 0x79,
+	0x52, 0x07, 0x4f, 0x66, 0x66, 0x73, 0x65, 0x74, 0x73, 0x12, 0x1e, 0x0a, 0x04, 0x44, 0x6f, 0x63,
+	0x73, 0x18, 0x16, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0a, 0x2e, 0x53, 0x6c, 0x69, 0x6d, 0x41, 0x72,
+	0x72, 0x61, 0x79, 0x52, 0x04, 0x44, 0x6f, 0x63, 0x73, 0x12, 0x20, 0x0a, 0x05, 0x46, 0x72, 0x65,
+	0x71, 0x73, 0x18, 0x17, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0a, 0x2e, 0x53, 0x6c, 0x69, 0x6d, 0x41,
+	0x72, 0x72, 0x61, 0x79, 0x52, 0x05, 0x46, 0x72, 0x65, 0x71, 0x73, 0x42, 0x2b, 0x5a, 0x29, 0x67,
+	0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x6f, 0x70, 0x65, 0x6e, 0x61, 0x63,
+	0x69, 0x64, 0x2f, 0x73, 0x6c, 0x69, 0x6d, 0x61, 0x72, 0x72, 0x61, 0x79, 0x2f, 0x69, 0x6e, 0x64,
+	0x65, 0x78, 0x3b, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_index_index_proto_rawDescOnce sync.Once
+	file_index_index_proto_rawDescData = file_index_index_proto_rawDesc
+)
+
+func file_index_index_proto_rawDescGZIP() []byte {
+	file_index_index_proto_rawDescOnce.Do(func() {
+		file_index_index_proto_rawDescData = protoimpl.X.CompressGZIP(file_index_index_proto_rawDescData)
+	})
+	return file_index_index_proto_rawDescData
+}
+
+var file_index_index_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_index_index_proto_goTypes = []interface{}{
+	(*Index)(nil),                 // 0: slimarray.index.Index
+	(*slimarray.SortedBytes)(nil), // 1: SortedBytes
+	(*slimarray.SlimArray)(nil),   // 2: SlimArray
+}
+var file_index_index_proto_depIdxs = []int32{
+	1, // 0: slimarray.index.Index.Terms:type_name -> SortedBytes
+	2, // 1: slimarray.index.Index.Offsets:type_name -> SlimArray
+	2, // 2: slimarray.index.Index.Docs:type_name -> SlimArray
+	2, // 3: slimarray.index.Index.Freqs:type_name -> SlimArray
+	4, // [4:4] is the sub-list for method output_type
+	4, // [4:4] is the sub-list for method input_type
+	4, // [4:4] is the sub-list for extension type_name
+	4, // [4:4] is the sub-list for extension extendee
+	0, // [0:4] is the sub-list for field type_name
+}
+
+func init() { file_index_index_proto_init() }
+func file_index_index_proto_init() {
+	if File_index_index_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_index_index_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Index); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_index_index_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   1,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_index_index_proto_goTypes,
+		DependencyIndexes: file_index_index_proto_depIdxs,
+		MessageInfos:      file_index_index_proto_msgTypes,
+	}.Build()
+	File_index_index_proto = out.File
+	file_index_index_proto_rawDesc = nil
+	file_index_index_proto_goTypes = nil
+	file_index_index_proto_depIdxs = nil
+}
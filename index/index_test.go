@@ -0,0 +1,256 @@
+package index_test
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/openacid/slimarray/index"
+	"github.com/stretchr/testify/require"
+)
+
+var Output int
+
+func TestBuilder(t *testing.T) {
+
+	ta := require.New(t)
+
+	b := index.NewBuilder()
+	b.AddDoc(3, []string{"foo", "bar", "foo"})
+	b.AddDoc(1, []string{"bar"})
+	b.AddDoc(7, []string{"baz", "foo"})
+	b.Add("bar", 1)
+
+	idx, err := b.Build()
+	ta.NoError(err)
+
+	ta.Equal(3, idx.Len())
+	ta.Equal("bar", idx.Term(0))
+	ta.Equal("baz", idx.Term(1))
+	ta.Equal("foo", idx.Term(2))
+
+	ta.Equal(2, idx.DocFreq("bar"))
+	ta.Equal(1, idx.DocFreq("baz"))
+	ta.Equal(2, idx.DocFreq("foo"))
+	ta.Equal(0, idx.DocFreq("ba"))
+	ta.Equal(0, idx.DocFreq("zzz"))
+
+	p := idx.Postings("foo")
+	var got [][2]uint32
+	for p.Next() {
+		got = append(got, [2]uint32{p.Doc(), p.Freq()})
+	}
+	ta.Equal([][2]uint32{{3, 2}, {7, 1}}, got)
+	ta.False(p.Next())
+
+	p = idx.Postings("bar")
+	ta.True(p.Next())
+	ta.Equal(uint32(1), p.Doc())
+	ta.Equal(uint32(2), p.Freq())
+
+	ta.Equal([]uint32{3, 7}, idx.And("foo"))
+	ta.Equal([]uint32{3}, idx.And("foo", "bar"))
+	ta.Equal([]uint32{}, idx.And("foo", "bar", "baz"))
+	ta.Equal([]uint32{}, idx.And("foo", "nonexistent"))
+	ta.Equal([]uint32{}, idx.And())
+
+	ta.Equal([]uint32{1, 3, 7}, idx.Or("foo", "bar"))
+	ta.Equal([]uint32{3, 7}, idx.Or("foo", "nonexistent"))
+	ta.Equal([]uint32{}, idx.Or())
+}
+
+func TestBuilder_empty(t *testing.T) {
+
+	ta := require.New(t)
+
+	idx, err := index.NewBuilder().Build()
+	ta.NoError(err)
+
+	ta.Equal(0, idx.Len())
+	ta.Equal(0, idx.DocFreq("foo"))
+	ta.False(idx.Postings("foo").Next())
+	ta.False(idx.Postings("foo").Advance(0))
+	ta.Equal([]uint32{}, idx.And("foo"))
+	ta.Equal([]uint32{}, idx.Or("foo"))
+}
+
+func TestPostings_Advance(t *testing.T) {
+
+	ta := require.New(t)
+
+	docs := []uint32{2, 4, 6, 8, 10, 20, 30, 100, 101, 102, 1000}
+
+	b := index.NewBuilder()
+	for _, d := range docs {
+		b.Add("a", d)
+	}
+	idx, err := b.Build()
+	ta.NoError(err)
+
+	// Advance from the start.
+	for target := uint32(0); target < 1002; target++ {
+		p := idx.Postings("a")
+		want := sort.Search(len(docs), func(i int) bool { return docs[i] >= target })
+		ok := p.Advance(target)
+		ta.Equal(want < len(docs), ok, "target: %d", target)
+		if ok {
+			ta.Equal(docs[want], p.Doc(), "target: %d", target)
+		}
+	}
+
+	// Advance never moves backward.
+	p := idx.Postings("a")
+	ta.True(p.Advance(30))
+	ta.Equal(uint32(30), p.Doc())
+	ta.True(p.Advance(5))
+	ta.Equal(uint32(30), p.Doc())
+	ta.True(p.Next())
+	ta.Equal(uint32(100), p.Doc())
+	ta.True(p.Advance(1000))
+	ta.Equal(uint32(1000), p.Doc())
+	ta.False(p.Advance(1001))
+	ta.False(p.Next())
+	ta.False(p.Advance(0))
+}
+
+func TestIndex_random(t *testing.T) {
+
+	ta := require.New(t)
+
+	idx, sets := randIndex(t, 2000, 50, 0)
+
+	terms := make([]string, 0, len(sets))
+	for term, s := range sets {
+		terms = append(terms, term)
+		ta.Equal(s, idx.Postings(term).Docs())
+		ta.Equal(len(s), idx.DocFreq(term))
+	}
+	sort.Strings(terms)
+
+	rnd := rand.New(rand.NewSource(1))
+
+	for i := 0; i < 500; i++ {
+		n := rnd.Intn(4) + 1
+		qs := make([]string, n)
+		for j := range qs {
+			qs[j] = terms[rnd.Intn(len(terms))]
+		}
+
+		ta.Equal(bruteAnd(sets, qs), idx.And(qs...), "terms: %v", qs)
+		ta.Equal(bruteOr(sets, qs), idx.Or(qs...), "terms: %v", qs)
+	}
+}
+
+func TestIndex_marshalUnmarshal(t *testing.T) {
+
+	ta := require.New(t)
+
+	idx, sets := randIndex(t, 500, 20, 2)
+
+	bytes, err := proto.Marshal(idx)
+	ta.NoError(err)
+
+	b := &index.Index{}
+	err = proto.Unmarshal(bytes, b)
+	ta.NoError(err)
+
+	ta.Equal(idx.Len(), b.Len())
+	for term, s := range sets {
+		ta.Equal(s, b.Postings(term).Docs())
+	}
+}
+
+// randIndex builds an Index of nDocs documents. Term "t<k>" appears in about
+// 1/(k+1) of the documents, thus posting lists have various lengths.
+// It returns the index and the sorted docs of every term.
+func randIndex(t testing.TB, nDocs, nTerms int, seed int64) (*index.Index, map[string][]uint32) {
+
+	rnd := rand.New(rand.NewSource(seed))
+
+	b := index.NewBuilder()
+	sets := map[string][]uint32{}
+
+	for d := 0; d < nDocs; d++ {
+		doc := uint32(d * 3)
+		for k := 0; k < nTerms; k++ {
+			if rnd.Intn(k+1) == 0 {
+				term := fmt.Sprintf("t%d", k)
+				b.Add(term, doc)
+				sets[term] = append(sets[term], doc)
+			}
+		}
+	}
+
+	idx, err := b.Build()
+	require.NoError(t, err)
+
+	return idx, sets
+}
+
+func bruteAnd(sets map[string][]uint32, terms []string) []uint32 {
+	rst := []uint32{}
+	for _, d := range sets[terms[0]] {
+		all := true
+		for _, t := range terms[1:] {
+			if !contains(sets[t], d) {
+				all = false
+				break
+			}
+		}
+		if all {
+			rst = append(rst, d)
+		}
+	}
+	return rst
+}
+
+func bruteOr(sets map[string][]uint32, terms []string) []uint32 {
+	m := map[uint32]bool{}
+	for _, t := range terms {
+		for _, d := range sets[t] {
+			m[d] = true
+		}
+	}
+	rst := []uint32{}
+	for d := range m {
+		rst = append(rst, d)
+	}
+	sort.Slice(rst, func(i, j int) bool { return rst[i] < rst[j] })
+	return rst
+}
+
+func contains(s []uint32, x uint32) bool {
+	i := sort.Search(len(s), func(i int) bool { return s[i] >= x })
+	return i < len(s) && s[i] == x
+}
+
+func BenchmarkIndex(b *testing.B) {
+
+	idx, _ := randIndex(b, 100000, 100, 0)
+
+	b.Run("And/long-short", func(b *testing.B) {
+		s := 0
+		for i := 0; i < b.N; i++ {
+			s += len(idx.And("t0", "t99"))
+		}
+		Output = s
+	})
+
+	b.Run("And/long-long", func(b *testing.B) {
+		s := 0
+		for i := 0; i < b.N; i++ {
+			s += len(idx.And("t1", "t2"))
+		}
+		Output = s
+	})
+
+	b.Run("Or", func(b *testing.B) {
+		s := 0
+		for i := 0; i < b.N; i++ {
+			s += len(idx.Or("t50", "t99"))
+		}
+		Output = s
+	})
+}
@@ -0,0 +1,117 @@
+package index
+
+import (
+	"sort"
+)
+
+// Postings iterates the posting list of a term, in ascending order of
+// document ids.
+// It is positioned before the first posting when created:
+//
+//	p := idx.Postings("foo")
+//	for p.Next() {
+//	    fmt.Println(p.Doc(), p.Freq())
+//	}
+//
+// Since 0.1.15
+type Postings struct {
+	idx *Index
+
+	// [start, end) is the range of postings in Index.Docs.
+	start, end int32
+
+	// i is the current position, start-1 before the first Next().
+	i int32
+}
+
+// Len returns the number of postings, i.e., the number of documents that
+// contain the term.
+//
+// Since 0.1.15
+func (p *Postings) Len() int {
+	return int(p.end - p.start)
+}
+
+// Next moves to the next posting. It returns false if there is no more
+// posting.
+//
+// Since 0.1.15
+func (p *Postings) Next() bool {
+	if p.i < p.end {
+		p.i++
+	}
+	return p.i < p.end
+}
+
+// Doc returns the document id of the current posting.
+//
+// Since 0.1.15
+func (p *Postings) Doc() uint32 {
+	return p.idx.Docs.Get(p.i)
+}
+
+// Freq returns the frequency of the term in the current document.
+//
+// Since 0.1.15
+func (p *Postings) Freq() uint32 {
+	return p.idx.Freqs.Get(p.i)
+}
+
+// Advance moves to the first posting, at or after the current one, whose
+// document id is not less than target.
+// It returns false if there is no such posting.
+//
+// It skips postings with galloping search: it checks the postings at distance
+// 1, 2, 4, 8... until one is not less than target, then binary searches in the
+// last interval. Thus it costs O(log(d)), where d is the distance moved.
+//
+// Since 0.1.15
+func (p *Postings) Advance(target uint32) bool {
+
+	lo := p.i
+	if lo < p.start {
+		lo = p.start
+	}
+
+	if lo >= p.end {
+		p.i = p.end
+		return false
+	}
+
+	docs := p.idx.Docs
+
+	if docs.Get(lo) >= target {
+		p.i = lo
+		return true
+	}
+
+	// docs[lo] < target
+
+	bound := int32(1)
+	for lo+bound < p.end && docs.Get(lo+bound) < target {
+		bound *= 2
+	}
+
+	// Every posting at or before lo+bound/2 is less than target.
+	s := lo + bound/2 + 1
+	e := lo + bound + 1
+	if e > p.end {
+		e = p.end
+	}
+
+	p.i = s + int32(sort.Search(int(e-s), func(j int) bool {
+		return docs.Get(s+int32(j)) >= target
+	}))
+
+	return p.i < p.end
+}
+
+// Docs returns the document ids of all postings, regardless of the current
+// position.
+//
+// Since 0.1.15
+func (p *Postings) Docs() []uint32 {
+	rst := make([]uint32, p.Len())
+	p.idx.Docs.Slice(p.start, p.end, rst)
+	return rst
+}
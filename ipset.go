@@ -0,0 +1,290 @@
+package slimarray
+
+import (
+	"encoding/binary"
+	"errors"
+	"net"
+	"sort"
+)
+
+var (
+	// InvalidIP is returned if an IP or a CIDR can not be parsed.
+	InvalidIP = errors.New("invalid IP")
+
+	// InvalidIPRange is returned if the start of a range is greater than the
+	// end, or the start and the end are not of the same IP version.
+	InvalidIPRange = errors.New("invalid IP range")
+)
+
+// IPRange is a range of IP addresses from Start to End, both inclusive.
+//
+// Since 0.1.15
+type IPRange struct {
+	Start, End net.IP
+}
+
+// ip6 is an IPv6 address in 4 32-bit lanes, the most significant lane first.
+type ip6 [4]uint32
+
+// ip6Range is a range of IPv6 addresses, both inclusive.
+type ip6Range struct {
+	start, end ip6
+}
+
+// ip4Range is a range of IPv4 addresses, both inclusive.
+type ip4Range struct {
+	start, end uint32
+}
+
+// NewIPSet creates an IPSet from ranges of IPv4 or IPv6 addresses.
+// Ranges may overlap and need not to be sorted.
+// An IPv4-mapped IPv6 address such as "::ffff:1.2.3.4" is an IPv4 address.
+//
+// It returns InvalidIPRange if the start of a range is greater than the end,
+// or they are not of the same IP version.
+//
+// Since 0.1.15
+func NewIPSet(ranges []IPRange) (*IPSet, error) {
+
+	var r4 []ip4Range
+	var r6 []ip6Range
+
+	for _, r := range ranges {
+		s4, e4 := r.Start.To4(), r.End.To4()
+		s16, e16 := r.Start.To16(), r.End.To16()
+
+		switch {
+		case s4 != nil && e4 != nil:
+			s, e := binary.BigEndian.Uint32(s4), binary.BigEndian.Uint32(e4)
+			if s > e {
+				return nil, InvalidIPRange
+			}
+			r4 = append(r4, ip4Range{s, e})
+
+		case s4 == nil && e4 == nil && s16 != nil && e16 != nil:
+			s, e := toIP6(s16), toIP6(e16)
+			if e.less(s) {
+				return nil, InvalidIPRange
+			}
+			r6 = append(r6, ip6Range{s, e})
+
+		default:
+			return nil, InvalidIPRange
+		}
+	}
+
+	r4 = mergeIP4Ranges(r4)
+	r6 = mergeIP6Ranges(r6)
+
+	if len(r4) > 0x7fffffff || len(r6) > 0x7fffffff {
+		return nil, TooManyRows
+	}
+
+	starts := make([]uint32, len(r4))
+	ends := make([]uint32, len(r4))
+	for i, r := range r4 {
+		starts[i] = r.start
+		ends[i] = r.end
+	}
+
+	set := &IPSet{
+		V4Starts: NewU32(starts),
+		V4Ends:   NewU32(ends),
+		V6Starts: make([]*SlimArray, 4),
+		V6Ends:   make([]*SlimArray, 4),
+	}
+
+	for lane := 0; lane < 4; lane++ {
+		starts := make([]uint32, len(r6))
+		ends := make([]uint32, len(r6))
+		for i, r := range r6 {
+			starts[i] = r.start[lane]
+			ends[i] = r.end[lane]
+		}
+		set.V6Starts[lane] = NewU32(starts)
+		set.V6Ends[lane] = NewU32(ends)
+	}
+
+	return set, nil
+}
+
+// NewIPSetFromCIDRs creates an IPSet from CIDRs such as "10.0.0.0/8" or
+// "2001:db8::/32".
+// It returns InvalidIP if a CIDR can not be parsed.
+//
+// Since 0.1.15
+func NewIPSetFromCIDRs(cidrs []string) (*IPSet, error) {
+
+	ranges := make([]IPRange, 0, len(cidrs))
+
+	for _, c := range cidrs {
+		_, ipnet, err := net.ParseCIDR(c)
+		if err != nil {
+			return nil, InvalidIP
+		}
+
+		start := ipnet.IP
+		end := make(net.IP, len(start))
+		for i := range start {
+			end[i] = start[i] | ^ipnet.Mask[i]
+		}
+
+		ranges = append(ranges, IPRange{Start: start, End: end})
+	}
+
+	return NewIPSet(ranges)
+}
+
+// Contains returns true if ip is in the set.
+// It returns false for an invalid ip.
+//
+// It is a binary search on SlimArray. A Contains() costs about 460 ns on a set
+// of 400,000 IPv4 ranges, while a binary search on a sorted []uint32 costs
+// about 160 ns.
+//
+// Since 0.1.15
+func (s *IPSet) Contains(ip net.IP) bool {
+
+	if ip4 := ip.To4(); ip4 != nil {
+		return s.containsV4(binary.BigEndian.Uint32(ip4))
+	}
+
+	if ip16 := ip.To16(); ip16 != nil {
+		return s.containsV6(toIP6(ip16))
+	}
+
+	return false
+}
+
+// Len returns the number of non-overlapping ranges of IPv4 and IPv6 in the set.
+//
+// Since 0.1.15
+func (s *IPSet) Len() int {
+	return s.v4Len() + s.v6Len()
+}
+
+// v4Len returns the number of IPv4 ranges.
+// A set unmarshaled without IPv4 arrays is treated as empty.
+func (s *IPSet) v4Len() int {
+	if s.V4Starts == nil || s.V4Ends == nil {
+		return 0
+	}
+	return s.V4Starts.Len()
+}
+
+// v6Len returns the number of IPv6 ranges.
+// A set unmarshaled with missing or short IPv6 lanes is treated as empty.
+func (s *IPSet) v6Len() int {
+	if len(s.V6Starts) != 4 || len(s.V6Ends) != 4 {
+		return 0
+	}
+	for lane := 0; lane < 4; lane++ {
+		if s.V6Starts[lane] == nil || s.V6Ends[lane] == nil {
+			return 0
+		}
+	}
+	return s.V6Starts[0].Len()
+}
+
+func (s *IPSet) containsV4(x uint32) bool {
+
+	// the first range that starts after x
+	i := sort.Search(s.v4Len(), func(i int) bool {
+		return s.V4Starts.Get(int32(i)) > x
+	})
+
+	if i == 0 {
+		return false
+	}
+	return x <= s.V4Ends.Get(int32(i-1))
+}
+
+func (s *IPSet) containsV6(x ip6) bool {
+
+	i := sort.Search(s.v6Len(), func(i int) bool {
+		return x.less(laneAt(s.V6Starts, int32(i)))
+	})
+
+	if i == 0 {
+		return false
+	}
+	return !laneAt(s.V6Ends, int32(i-1)).less(x)
+}
+
+// laneAt returns the i-th IPv6 address stored in 4 lanes.
+func laneAt(lanes []*SlimArray, i int32) ip6 {
+	var x ip6
+	for lane := range x {
+		x[lane] = lanes[lane].Get(i)
+	}
+	return x
+}
+
+func toIP6(ip net.IP) ip6 {
+	var x ip6
+	for lane := range x {
+		x[lane] = binary.BigEndian.Uint32(ip[lane*4:])
+	}
+	return x
+}
+
+func (a ip6) less(b ip6) bool {
+	for lane := range a {
+		if a[lane] != b[lane] {
+			return a[lane] < b[lane]
+		}
+	}
+	return false
+}
+
+// next returns a+1 and false if it overflows.
+func (a ip6) next() (ip6, bool) {
+	for lane := 3; lane >= 0; lane-- {
+		a[lane]++
+		if a[lane] != 0 {
+			return a, true
+		}
+	}
+	return a, false
+}
+
+// mergeIP4Ranges sorts ranges and merges overlapping or adjacent ones.
+func mergeIP4Ranges(rs []ip4Range) []ip4Range {
+
+	sort.Slice(rs, func(i, j int) bool { return rs[i].start < rs[j].start })
+
+	var merged []ip4Range
+	for _, r := range rs {
+		l := len(merged)
+		if l > 0 && (merged[l-1].end == 0xffffffff || r.start <= merged[l-1].end+1) {
+			if r.end > merged[l-1].end {
+				merged[l-1].end = r.end
+			}
+			continue
+		}
+		merged = append(merged, r)
+	}
+	return merged
+}
+
+// mergeIP6Ranges sorts ranges and merges overlapping or adjacent ones.
+func mergeIP6Ranges(rs []ip6Range) []ip6Range {
+
+	sort.Slice(rs, func(i, j int) bool { return rs[i].start.less(rs[j].start) })
+
+	var merged []ip6Range
+	for _, r := range rs {
+		l := len(merged)
+		if l > 0 {
+			next, ok := merged[l-1].end.next()
+			if !ok || !next.less(r.start) {
+				if merged[l-1].end.less(r.end) {
+					merged[l-1].end = r.end
+				}
+				continue
+			}
+		}
+		merged = append(merged, r)
+	}
+	return merged
+}
@@ -0,0 +1,327 @@
+package slimarray
+
+import (
+	"encoding/binary"
+	"math/rand"
+	"net"
+	"sort"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewIPSetFromCIDRs(t *testing.T) {
+
+	ta := require.New(t)
+
+	s, err := NewIPSetFromCIDRs([]string{
+		"10.0.0.0/8",
+		"192.168.1.0/24",
+		"192.168.2.0/24",
+		"1.2.3.4/32",
+		"255.255.255.0/24",
+		"2001:db8::/32",
+		"fe80::1/128",
+		"ffff:ffff:ffff:ffff:ffff:ffff:ffff:ff00/120",
+	})
+	ta.NoError(err)
+
+	// 192.168.1.0/24 and 192.168.2.0/24 are merged
+	ta.Equal(4, s.V4Starts.Len())
+	ta.Equal(3, s.V6Starts[0].Len())
+	ta.Equal(7, s.Len())
+
+	cases := []struct {
+		ip   string
+		want bool
+	}{
+		{"9.255.255.255", false},
+		{"10.0.0.0", true},
+		{"10.1.2.3", true},
+		{"10.255.255.255", true},
+		{"11.0.0.0", false},
+		{"192.168.0.255", false},
+		{"192.168.1.0", true},
+		{"192.168.2.255", true},
+		{"192.168.3.0", false},
+		{"1.2.3.3", false},
+		{"1.2.3.4", true},
+		{"1.2.3.5", false},
+		{"0.0.0.0", false},
+		{"255.255.255.255", true},
+		{"::ffff:10.1.1.1", true},
+
+		{"2001:db8::", true},
+		{"2001:db8:ffff:ffff:ffff:ffff:ffff:ffff", true},
+		{"2001:db9::", false},
+		{"2001:db7:ffff:ffff:ffff:ffff:ffff:ffff", false},
+		{"fe80::1", true},
+		{"fe80::2", false},
+		{"fe80::", false},
+		{"::", false},
+		{"ffff:ffff:ffff:ffff:ffff:ffff:ffff:ffff", true},
+		{"ffff:ffff:ffff:ffff:ffff:ffff:ffff:feff", false},
+
+		// an IPv6 address whose low 32 bits is an IPv4 in the set
+		{"::10.1.1.1", false},
+	}
+
+	for _, c := range cases {
+		ip := net.ParseIP(c.ip)
+		ta.NotNil(ip, c.ip)
+		ta.Equal(c.want, s.Contains(ip), c.ip)
+	}
+
+	ta.False(s.Contains(nil))
+	ta.False(s.Contains(net.IP{1, 2, 3}))
+
+	_, err = NewIPSetFromCIDRs([]string{"10.0.0.0/33"})
+	ta.Equal(InvalidIP, err)
+}
+
+func TestNewIPSet(t *testing.T) {
+
+	ta := require.New(t)
+
+	r := func(s, e string) IPRange {
+		return IPRange{Start: net.ParseIP(s), End: net.ParseIP(e)}
+	}
+
+	s, err := NewIPSet([]IPRange{
+		r("1.0.0.5", "1.0.0.9"),
+		r("1.0.0.0", "1.0.0.3"),
+		r("1.0.0.4", "1.0.0.4"), // adjacent to both
+		r("1.0.0.7", "1.0.0.20"),
+		r("2.0.0.0", "2.0.0.0"),
+		r("::1", "::5"),
+		r("::3", "::10"),
+		r("::12", "::12"),
+	})
+	ta.NoError(err)
+
+	ta.Equal([]uint32{0x01000000, 0x02000000}, u32s(s.V4Starts))
+	ta.Equal([]uint32{0x01000014, 0x02000000}, u32s(s.V4Ends))
+	ta.Equal([]uint32{1, 0x12}, u32s(s.V6Starts[3]))
+	ta.Equal([]uint32{0x10, 0x12}, u32s(s.V6Ends[3]))
+
+	ta.True(s.Contains(net.ParseIP("1.0.0.20")))
+	ta.False(s.Contains(net.ParseIP("1.0.0.21")))
+	ta.True(s.Contains(net.ParseIP("::10")))
+	ta.False(s.Contains(net.ParseIP("::11")))
+
+	// empty set
+	s, err = NewIPSet(nil)
+	ta.NoError(err)
+	ta.Equal(0, s.Len())
+	ta.False(s.Contains(net.ParseIP("1.2.3.4")))
+	ta.False(s.Contains(net.ParseIP("::1")))
+
+	// full ranges
+	s, err = NewIPSet([]IPRange{
+		r("0.0.0.0", "255.255.255.255"),
+		r("1.0.0.0", "2.0.0.0"),
+		r("::", "ffff:ffff:ffff:ffff:ffff:ffff:ffff:ffff"),
+		r("::1", "::2"),
+	})
+	ta.NoError(err)
+	ta.Equal(2, s.Len())
+	ta.True(s.Contains(net.ParseIP("0.0.0.0")))
+	ta.True(s.Contains(net.ParseIP("255.255.255.255")))
+	ta.True(s.Contains(net.ParseIP("::")))
+	ta.True(s.Contains(net.ParseIP("ffff:ffff:ffff:ffff:ffff:ffff:ffff:ffff")))
+
+	// invalid
+	for _, rr := range []IPRange{
+		r("1.0.0.1", "1.0.0.0"),
+		r("::2", "::1"),
+		r("1.0.0.0", "::1"),
+		{Start: nil, End: nil},
+	} {
+		_, err = NewIPSet([]IPRange{rr})
+		ta.Equal(InvalidIPRange, err, "%v", rr)
+	}
+}
+
+func TestIPSet_random(t *testing.T) {
+
+	ta := require.New(t)
+
+	rnd := rand.New(rand.NewSource(0))
+
+	var ranges []IPRange
+	var raw4 []ip4Range
+
+	for i := 0; i < 2000; i++ {
+		s := rnd.Uint32()
+		e := s + uint32(rnd.Intn(1000))
+		if e < s {
+			e = s
+		}
+		raw4 = append(raw4, ip4Range{s, e})
+		ranges = append(ranges, IPRange{Start: u32ToIP(s), End: u32ToIP(e)})
+	}
+
+	s, err := NewIPSet(ranges)
+	ta.NoError(err)
+
+	bruteForce := func(x uint32) bool {
+		for _, r := range raw4 {
+			if r.start <= x && x <= r.end {
+				return true
+			}
+		}
+		return false
+	}
+
+	for i := 0; i < 2000; i++ {
+		var x uint32
+		if i%2 == 0 {
+			r := raw4[rnd.Intn(len(raw4))]
+			x = r.start + uint32(rnd.Intn(1002)) - 1
+		} else {
+			x = rnd.Uint32()
+		}
+		ta.Equal(bruteForce(x), s.Contains(u32ToIP(x)), "%s", u32ToIP(x))
+	}
+}
+
+func TestIPSet_marshalUnmarshal(t *testing.T) {
+
+	ta := require.New(t)
+
+	s, err := NewIPSetFromCIDRs([]string{"10.0.0.0/8", "2001:db8::/32"})
+	ta.NoError(err)
+
+	bytes, err := proto.Marshal(s)
+	ta.NoError(err)
+
+	b := &IPSet{}
+	err = proto.Unmarshal(bytes, b)
+	ta.NoError(err)
+
+	ta.True(b.Contains(net.ParseIP("10.2.3.4")))
+	ta.False(b.Contains(net.ParseIP("11.2.3.4")))
+	ta.True(b.Contains(net.ParseIP("2001:db8::1")))
+	ta.False(b.Contains(net.ParseIP("2001:db9::1")))
+}
+
+func TestIPSet_missingLanes(t *testing.T) {
+
+	ta := require.New(t)
+
+	s, err := NewIPSetFromCIDRs([]string{"10.0.0.0/8", "2001:db8::/32"})
+	ta.NoError(err)
+
+	cases := []struct {
+		set     *IPSet
+		wantLen int
+	}{
+		{&IPSet{}, 0},
+		{&IPSet{V4Starts: s.V4Starts, V4Ends: s.V4Ends}, 1},
+		{&IPSet{V4Starts: s.V4Starts, V4Ends: s.V4Ends, V6Starts: s.V6Starts[:2], V6Ends: s.V6Ends}, 1},
+		{&IPSet{V4Starts: s.V4Starts, V4Ends: s.V4Ends, V6Starts: s.V6Starts, V6Ends: s.V6Ends[:3]}, 1},
+	}
+
+	for i, c := range cases {
+		bytes, err := proto.Marshal(c.set)
+		ta.NoError(err)
+
+		b := &IPSet{}
+		ta.NoError(proto.Unmarshal(bytes, b))
+
+		// missing or short IPv6 lanes are treated as empty
+		ta.Equal(c.wantLen, b.Len(), "%d-th", i+1)
+		ta.Equal(c.wantLen == 1, b.Contains(net.ParseIP("10.2.3.4")), "%d-th", i+1)
+		ta.False(b.Contains(net.ParseIP("2001:db8::1")), "%d-th", i+1)
+	}
+}
+
+func TestIPSet_iplist(t *testing.T) {
+
+	ta := require.New(t)
+
+	ips := loadIPList(ta)
+	s := ipSetOf(ips)
+
+	sorted := append([]uint32{}, ips...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	for i, x := range sorted {
+		ta.True(s.Contains(u32ToIP(x)))
+		if i > 0 && sorted[i-1] < x-1 {
+			ta.False(s.Contains(u32ToIP(x - 1)))
+		}
+	}
+
+	size := proto.Size(s)
+	t.Logf("ips: %d ranges: %d serialized: %d bytes, sorted []uint32: %d bytes",
+		len(ips), s.Len(), size, len(ips)*4)
+}
+
+func ipSetOf(ips []uint32) *IPSet {
+	ranges := make([]IPRange, len(ips))
+	for i, x := range ips {
+		ranges[i] = IPRange{Start: u32ToIP(x), End: u32ToIP(x)}
+	}
+	s, err := NewIPSet(ranges)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+func u32ToIP(x uint32) net.IP {
+	ip := make(net.IP, 4)
+	binary.BigEndian.PutUint32(ip, x)
+	return ip
+}
+
+func u32s(a *SlimArray) []uint32 {
+	rst := make([]uint32, a.Len())
+	for i := range rst {
+		rst[i] = a.Get(int32(i))
+	}
+	return rst
+}
+
+func BenchmarkIPSet_Contains(b *testing.B) {
+
+	ips := loadIPList(require.New(b))
+	s := ipSetOf(ips)
+
+	sorted := append([]uint32{}, ips...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	rnd := rand.New(rand.NewSource(0))
+	probes := make([]net.IP, 1024)
+	for i := range probes {
+		x := sorted[rnd.Intn(len(sorted))]
+		if i%2 == 1 {
+			x++
+		}
+		probes[i] = u32ToIP(x)
+	}
+
+	b.Run("IPSet", func(b *testing.B) {
+		n := 0
+		for i := 0; i < b.N; i++ {
+			if s.Contains(probes[i&1023]) {
+				n++
+			}
+		}
+		Output = n
+	})
+
+	b.Run("sorted_uint32", func(b *testing.B) {
+		n := 0
+		for i := 0; i < b.N; i++ {
+			x := binary.BigEndian.Uint32(probes[i&1023].To4())
+			j := sort.Search(len(sorted), func(j int) bool { return sorted[j] >= x })
+			if j < len(sorted) && sorted[j] == x {
+				n++
+			}
+		}
+		Output = n
+	})
+}
@@ -0,0 +1,132 @@
+package slimarray
+
+import (
+	"math"
+
+	"github.com/openacid/slimarray/polyfit"
+)
+
+// NewU32Lossy creates a lossy "SlimArray" array from a slice of uint32, in
+// which an elt may differ from the original one by at most maxErr:
+//
+//    |Get(i) - nums[i]| <= maxErr
+//
+// A residual is quantized by 2*maxErr+1, thus it requires
+// about log₂(2*maxErr+1) less bits than a lossless SlimArray.
+// A maxErr of 0 is the same as NewU32().
+//
+// Since 0.1.15
+func NewU32Lossy(nums []uint32, maxErr uint32) *SlimArray {
+	return newU32(nums, maxErr, nil)
+}
+
+// newSegLossy builds a segment in which a residual r is quantized to
+// ⌊(r+maxErr) / (2*maxErr+1)⌋.
+//
+// Spans are split and fitted with ys scaled down by 2*maxErr+1, which is
+// approximately the same as fitting the quantized residuals.
+//
+// Since 0.1.15
+func newSegLossy(nums []uint32, start int64, opt *Opt, maxErr uint32) (uint64, []float64, []int64, []uint64) {
+
+	n := int32(len(nums))
+	ys := make([]float64, n)
+
+	step := float64(lossyStep(maxErr))
+	for i, v := range nums {
+		ys[i] = float64(v) / step
+	}
+
+	spans := fitSpans(ys, opt)
+	for _, sp := range spans {
+		sp.fitQuantized(nums, maxErr)
+	}
+
+	return packSpans(spans, n, start, func(j int32, v float64) uint64 {
+		return uint64(quantize(int64(nums[j])-int64(v), maxErr))
+	})
+}
+
+// fitQuantized scales up the polynomial of a span fitted with scaled ys, and
+// makes sure every quantized residual of a span of a lossy SlimArray is
+// non-negative and fits in residualWidth bits.
+//
+// Since 0.1.15
+func (sp *span) fitQuantized(nums []uint32, maxErr uint32) {
+
+	step := float64(lossyStep(maxErr))
+	for i := range sp.poly {
+		sp.poly[i] *= step
+	}
+
+	for k := 0; k < 8; k++ {
+		max, min := int64(math.MinInt64), int64(math.MaxInt64)
+		for j := sp.s; j < sp.e; j++ {
+			v := int64(polyfit.Polynomial(sp.poly).Eval(float64(j)))
+			d := int64(nums[j]) - v
+			if d > max {
+				max = d
+			}
+			if d < min {
+				min = d
+			}
+		}
+
+		if min >= 0 {
+			sp.residualWidth = marginWidth(quantize(max, maxErr))
+			sp.mem = memCost(sp.poly, sp.residualWidth, sp.e-sp.s)
+			return
+		}
+
+		// move the curve down so that residuals are all non-negative.
+		p0 := sp.poly[0] + float64(min)
+		if p0 == sp.poly[0] {
+			p0 = math.Nextafter(p0, math.Inf(-1))
+		}
+		sp.poly[0] = p0
+	}
+
+	// The curve does not converge, e.g., the polynomial is not finite.
+	// Use a zero polynomial so that every residual is the elt itself, which
+	// is always non-negative.
+	max := int64(0)
+	for j := sp.s; j < sp.e; j++ {
+		if int64(nums[j]) > max {
+			max = int64(nums[j])
+		}
+	}
+
+	for i := range sp.poly {
+		sp.poly[i] = 0
+	}
+	sp.residualWidth = marginWidth(quantize(max, maxErr))
+	sp.mem = memCost(sp.poly, sp.residualWidth, sp.e-sp.s)
+}
+
+// lossyStep returns the quantization step of residuals of a lossy SlimArray.
+func lossyStep(maxErr uint32) int64 {
+	return 2*int64(maxErr) + 1
+}
+
+// quantize converts a non-negative residual r to the index of the nearest
+// multiple of 2*maxErr+1, i.e., |r - quantize(r)*(2*maxErr+1)| <= maxErr.
+func quantize(r int64, maxErr uint32) int64 {
+	return (r + int64(maxErr)) / lossyStep(maxErr)
+}
+
+// dequantize returns an elt of a lossy SlimArray from the value v evaluated
+// with the polynomial and the quantized residual q.
+// The result is clamped into the range of uint32, which only reduces the
+// error.
+//
+// Since 0.1.15
+func (sm *SlimArray) dequantize(v int64, q uint64) uint32 {
+	x := v + int64(q)*lossyStep(sm.MaxErr)
+	if x < 0 {
+		return 0
+	}
+	if x > math.MaxUint32 {
+		return math.MaxUint32
+	}
+	return uint32(x)
+}
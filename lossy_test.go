@@ -0,0 +1,180 @@
+package slimarray
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+	"testing/quick"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewU32Lossy(t *testing.T) {
+
+	ta := require.New(t)
+
+	for _, maxErr := range []uint32{0, 1, 2, 7, 100} {
+		a := NewU32Lossy(testNums, maxErr)
+		ta.Equal(maxErr, a.MaxErr)
+		ta.Equal(len(testNums), a.Len())
+
+		testLossy(ta, a, testNums, maxErr)
+	}
+
+	// maxErr=0 is lossless
+	ta.Equal(NewU32(testNums), NewU32Lossy(testNums, 0))
+}
+
+func TestNewU32Lossy_empty(t *testing.T) {
+
+	ta := require.New(t)
+
+	a := NewU32Lossy(nil, 3)
+	ta.Equal(0, a.Len())
+}
+
+func TestNewU32Lossy_bound(t *testing.T) {
+
+	// |Get(i) - nums[i]| <= maxErr holds for any array and any maxErr.
+
+	f := func(nums []uint32, maxErr uint32, shift uint8) bool {
+
+		// Reduce the bit width to make it more likely to be a trend
+		for i := range nums {
+			nums[i] >>= shift % 32
+		}
+		maxErr >>= shift % 32
+
+		a := NewU32Lossy(nums, maxErr)
+		rst := make([]uint32, len(nums))
+		a.Slice(0, int32(len(nums)), rst)
+
+		for i, v := range nums {
+			if absDiff(a.Get(int32(i)), v) > maxErr {
+				return false
+			}
+			if absDiff(rst[i], v) > maxErr {
+				return false
+			}
+		}
+		return true
+	}
+
+	err := quick.Check(f, &quick.Config{MaxCount: 500})
+	require.NoError(t, err)
+}
+
+func TestNewU32Lossy_extreme(t *testing.T) {
+
+	ta := require.New(t)
+
+	// Values close to 0 or math.MaxUint32 are quantized outside the range of
+	// uint32 and must be clamped.
+
+	rnd := rand.New(rand.NewSource(0))
+
+	n := 3000
+	nums := make([]uint32, n)
+	for i := range nums {
+		switch rnd.Intn(3) {
+		case 0:
+			nums[i] = uint32(rnd.Intn(10))
+		case 1:
+			nums[i] = math.MaxUint32 - uint32(rnd.Intn(10))
+		default:
+			nums[i] = rnd.Uint32()
+		}
+	}
+
+	for _, maxErr := range []uint32{1, 5, 1000, math.MaxUint32 / 4, math.MaxUint32} {
+		a := NewU32Lossy(nums, maxErr)
+		testLossy(ta, a, nums, maxErr)
+	}
+}
+
+func TestSpan_fitQuantized_notConverge(t *testing.T) {
+
+	ta := require.New(t)
+
+	nums := []uint32{5, 100, 3, math.MaxUint32}
+	maxErr := uint32(2)
+
+	// A NaN curve never makes residuals non-negative.
+	sp := &span{
+		poly: []float64{math.NaN(), 0, 0},
+		s:    0,
+		e:    int32(len(nums)),
+	}
+	sp.fitQuantized(nums, maxErr)
+
+	ta.Equal([]float64{0, 0, 0}, sp.poly)
+	ta.Equal(uint32(32), sp.residualWidth)
+	ta.Equal(memCost(sp.poly, 32, 4), sp.mem)
+}
+
+func TestNewU32Lossy_compression(t *testing.T) {
+
+	ta := require.New(t)
+
+	nums := loadSlimstar(ta)
+
+	lossless := NewU32(nums)
+	prev := spanBits(lossless)
+
+	for _, maxErr := range []uint32{1, 3, 7, 15} {
+		a := NewU32Lossy(nums, maxErr)
+		testLossy(ta, a, nums, maxErr)
+
+		bits := spanBits(a)
+		ta.Less(bits, prev, "maxErr: %d", maxErr)
+		prev = bits
+
+		t.Logf("maxErr: %2d bits/elt: %.3f (lossless: %.3f)",
+			maxErr,
+			float64(bits)/float64(len(nums)),
+			float64(spanBits(lossless))/float64(len(nums)))
+	}
+}
+
+func TestNewU32Lossy_marshalUnmarshal(t *testing.T) {
+
+	ta := require.New(t)
+
+	a := NewU32Lossy(testNums, 5)
+
+	bytes, err := proto.Marshal(a)
+	ta.NoError(err)
+
+	b := &SlimArray{}
+	err = proto.Unmarshal(bytes, b)
+	ta.NoError(err)
+
+	ta.Equal(uint32(5), b.MaxErr)
+	testLossy(ta, b, testNums, 5)
+}
+
+func testLossy(ta *require.Assertions, a *SlimArray, nums []uint32, maxErr uint32) {
+
+	rst := make([]uint32, len(nums))
+	a.Slice(0, int32(len(nums)), rst)
+
+	for i, v := range nums {
+		got := a.Get(int32(i))
+		ta.LessOrEqual(absDiff(got, v), maxErr, "i: %d, maxErr: %d", i, maxErr)
+		ta.Equal(got, rst[i], "Slice, i: %d", i)
+
+		if i < len(nums)-1 {
+			v1, v2 := a.Get2(int32(i))
+			ta.Equal(got, v1, "Get2, i: %d", i)
+			ta.Equal(a.Get(int32(i+1)), v2, "Get2, i: %d", i)
+		}
+	}
+}
+
+func absDiff(a, b uint32) uint32 {
+	if a > b {
+		return a - b
+	}
+	return b - a
+}
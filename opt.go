@@ -0,0 +1,68 @@
+package slimarray
+
+import "fmt"
+
+// BuildMode specifies how hard to search for a span layout with less memory
+// cost when building a SlimArray.
+//
+// Since 0.1.15
+type BuildMode int
+
+const (
+	// Balanced greedily merges adjacent spans until no merge reduces memory
+	// cost. It is the default mode.
+	Balanced BuildMode = iota
+
+	// FastBuild does not merge spans. Every span has 64 elts.
+	// It builds several times faster and costs more memory.
+	FastBuild
+
+	// MaxCompression finds the span layout with minimal memory cost with
+	// dynamic programming. It builds much slower.
+	MaxCompression
+)
+
+const (
+	// Span size in FastBuild mode.
+	fastSpanSize = int32(64)
+)
+
+// Opt specifies options to build a SlimArray.
+//
+// Since 0.1.15
+type Opt struct {
+	// Mode specifies how to split a segment into spans.
+	// By default it is Balanced.
+	Mode BuildMode
+
+	// Minimax specifies whether to try fitting every span with a minimax
+	// polynomial, which minimizes the max residual, instead of the least
+	// squares polynomial.
+	// The minimax polynomial is used only when it requires narrower residuals.
+	// It builds slower.
+	Minimax bool
+}
+
+// String returns the name of a build mode.
+//
+// Since 0.1.15
+func (m BuildMode) String() string {
+	switch m {
+	case Balanced:
+		return "Balanced"
+	case FastBuild:
+		return "FastBuild"
+	case MaxCompression:
+		return "MaxCompression"
+	default:
+		return fmt.Sprintf("BuildMode(%d)", int(m))
+	}
+}
+
+// withDefault returns a non-nil option.
+func (opt *Opt) withDefault() *Opt {
+	if opt == nil {
+		return &Opt{}
+	}
+	return opt
+}
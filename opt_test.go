@@ -0,0 +1,321 @@
+package slimarray
+
+import (
+	"bufio"
+	"compress/gzip"
+	"fmt"
+	"math"
+	"math/rand"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/openacid/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+var buildModes = []BuildMode{Balanced, FastBuild, MaxCompression}
+
+func TestNewU32Opt(t *testing.T) {
+
+	ta := require.New(t)
+
+	cases := [][]uint32{
+		{},
+		{1},
+		testNums[:17],
+		testNums,
+		testutil.RandU32Slice(0, 1024*3+5, 100),
+	}
+
+	for _, mode := range buildModes {
+		for _, nums := range cases {
+			a := NewU32Opt(nums, &Opt{Mode: mode})
+			testGet(ta, a, nums)
+		}
+
+		nums := testutil.RandU32Slice(0, 1024*3+5, 100)
+		nums64 := make([]uint64, len(nums))
+		for i, v := range nums {
+			nums64[i] = uint64(v) << 20
+		}
+		a := NewU64Opt(nums64, &Opt{Mode: mode})
+		for i, v := range nums64 {
+			ta.Equal(v, a.GetU64(int32(i)))
+		}
+	}
+
+	ta.Equal(NewU32(testNums), NewU32Opt(testNums, nil))
+	ta.Equal(NewU32(testNums), NewU32Opt(testNums, &Opt{Mode: Balanced}))
+}
+
+func TestNewU32Opt_FastBuild(t *testing.T) {
+
+	ta := require.New(t)
+
+	a := NewU32Opt(testNums, &Opt{Mode: FastBuild})
+	for _, seg := range a.Segments() {
+		for _, sp := range seg.Spans {
+			ta.True(sp.End-sp.Start <= 64)
+			ta.True(sp.Start%64 == 0)
+		}
+	}
+}
+
+func TestNewU32Opt_MaxCompression(t *testing.T) {
+
+	ta := require.New(t)
+
+	cases := [][]uint32{
+		testNums,
+		testutil.RandU32Slice(0, 1024*3+5, 100),
+		loadSlimstar(ta),
+	}
+
+	for i, nums := range cases {
+		balanced := spanBits(NewU32(nums))
+		optimal := spanBits(NewU32Opt(nums, &Opt{Mode: MaxCompression}))
+		fmt.Printf("%d-th: Balanced: %d bits, MaxCompression: %d bits\n", i+1, balanced, optimal)
+
+		ta.True(optimal <= balanced, "%d-th", i+1)
+	}
+}
+
+func TestNewU32Opt_Minimax(t *testing.T) {
+
+	ta := require.New(t)
+
+	cases := [][]uint32{
+		testNums,
+		testutil.RandU32Slice(0, 1024*3+5, 100),
+		loadSlimstar(ta),
+	}
+
+	for _, mode := range buildModes {
+		for i, nums := range cases {
+			lsq := NewU32Opt(nums, &Opt{Mode: mode})
+			a := NewU32Opt(nums, &Opt{Mode: mode, Minimax: true})
+			testGet(ta, a, nums)
+
+			fmt.Printf("%d-th %s: least squares: %d bits, minimax: %d bits\n",
+				i+1, mode, spanBits(lsq), spanBits(a))
+
+			ta.True(spanBits(a) <= spanBits(lsq), "%d-th %s", i+1, mode)
+		}
+	}
+
+	nums := testutil.RandU32Slice(0, 1024*3+5, 100)
+	nums64 := make([]uint64, len(nums))
+	for i, v := range nums {
+		nums64[i] = uint64(v) << 20
+	}
+	a := NewU64Opt(nums64, &Opt{Minimax: true})
+	for i, v := range nums64 {
+		ta.Equal(v, a.GetU64(int32(i)))
+	}
+}
+
+func TestFindOptimalFittings_bruteForce(t *testing.T) {
+
+	ta := require.New(t)
+
+	for seed := int64(0); seed < 50; seed++ {
+		rnd := rand.New(rand.NewSource(seed))
+
+		nUnits := 1 + rnd.Intn(8)
+		ys := randSegment(rnd, int(spanUnit)*nUnits-rnd.Intn(int(spanUnit)))
+		fts := initFittings(int32(len(ys)), ys, spanUnit)
+
+		got := spansMem(findOptimalFittings(ys, fts))
+
+		// enumerate every layout: the i-th bit of layout indicates whether
+		// a span ends at the i-th span unit.
+		n := len(fts)
+		want := math.MaxInt32
+		for layout := 0; layout < 1<<uint(n-1); layout++ {
+			mem := 0
+			s := 0
+			for e := 1; e <= n; e++ {
+				if e < n && layout&(1<<uint(e-1)) == 0 {
+					continue
+				}
+
+				ft := fts[s].Copy()
+				for u := s + 1; u < e; u++ {
+					ft.Merge(fts[u])
+				}
+				mem += newSpan(ys, ft, int32(s)*spanUnit, int32(ft.N)+int32(s)*spanUnit).mem
+				s = e
+			}
+
+			if mem < want {
+				want = mem
+			}
+		}
+
+		ta.Equal(want, got, "seed: %d, units: %d", seed, n)
+	}
+}
+
+func TestFindOptimalFittings_notWorseThanGreedy(t *testing.T) {
+
+	ta := require.New(t)
+
+	for seed := int64(0); seed < 200; seed++ {
+		rnd := rand.New(rand.NewSource(seed))
+
+		ys := randSegment(rnd, 1+rnd.Intn(segSize))
+
+		greedy := spansMem(findMinFittings(ys))
+		optimal := spansMem(findOptimalFittings(ys, initFittings(int32(len(ys)), ys, spanUnit)))
+
+		ta.True(optimal <= greedy, "seed: %d, greedy: %d, optimal: %d", seed, greedy, optimal)
+	}
+}
+
+func TestFindMinFittings_mergeFirstPair(t *testing.T) {
+
+	ta := require.New(t)
+
+	// The first 2 span units are on a line, the others are random.
+	// Only merging the first 2 units reduces memory cost.
+	rnd := rand.New(rand.NewSource(0))
+	ys := make([]float64, spanUnit*4)
+	for i := range ys {
+		if i < int(spanUnit)*2 {
+			ys[i] = float64(1000 + i*3)
+		} else {
+			ys[i] = float64(rnd.Intn(1 << 30))
+		}
+	}
+
+	spans := findMinFittings(ys)
+
+	ta.Equal(int32(0), spans[0].s)
+	ta.Equal(spanUnit*2, spans[0].e)
+}
+
+// randSegment generates n numbers in one of several distributions.
+func randSegment(rnd *rand.Rand, n int) []float64 {
+
+	ys := make([]float64, n)
+
+	switch rnd.Intn(4) {
+	case 0:
+		// random
+		for i := range ys {
+			ys[i] = float64(rnd.Intn(1 << 20))
+		}
+	case 1:
+		// sorted, with random steps
+		v := 0
+		for i := range ys {
+			v += rnd.Intn(100)
+			ys[i] = float64(v)
+		}
+	case 2:
+		// sorted, with occasional big jumps
+		v := 0
+		for i := range ys {
+			v += rnd.Intn(10)
+			if rnd.Intn(50) == 0 {
+				v += rnd.Intn(1 << 16)
+			}
+			ys[i] = float64(v)
+		}
+	default:
+		// a curve with noise
+		for i := range ys {
+			x := float64(i)
+			ys[i] = 1000 + 3*x + x*x/64 + float64(rnd.Intn(32))
+		}
+	}
+
+	return ys
+}
+
+// spansMem sums up memory cost of spans.
+func spansMem(spans []*span) int {
+	rst := 0
+	for _, sp := range spans {
+		rst += sp.mem
+	}
+	return rst
+}
+
+// spanBits sums up memory cost of every span.
+func spanBits(a *SlimArray) int64 {
+	rst := int64(0)
+	for _, seg := range a.Segments() {
+		for _, sp := range seg.Spans {
+			rst += sp.Bits
+		}
+	}
+	return rst
+}
+
+func loadSlimstar(ta *require.Assertions) []uint32 {
+	f, err := os.Open("example/slimstar/slim-stars-2019-02-02-to-2020-11-14.txt")
+	ta.NoError(err)
+	defer f.Close()
+
+	nums := []uint32{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		v, err := strconv.ParseUint(strings.TrimSpace(scanner.Text()), 10, 32)
+		ta.NoError(err)
+		nums = append(nums, uint32(v))
+	}
+	ta.NoError(scanner.Err())
+	return nums
+}
+
+// loadIPList reads ip list from the go source in example/iplist.
+func loadIPList(ta *require.Assertions) []uint32 {
+	f, err := os.Open("example/iplist/iplist.go.gz")
+	ta.NoError(err)
+	defer f.Close()
+
+	r, err := gzip.NewReader(f)
+	ta.NoError(err)
+
+	nums := []uint32{}
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if !strings.HasPrefix(line, "0x") {
+			continue
+		}
+		v, err := strconv.ParseUint(strings.TrimSuffix(line[2:], ","), 16, 32)
+		ta.NoError(err)
+		nums = append(nums, uint32(v))
+	}
+	ta.NoError(scanner.Err())
+	return nums
+}
+
+func BenchmarkNewU32Opt(b *testing.B) {
+
+	ta := require.New(b)
+
+	datasets := []struct {
+		name string
+		nums []uint32
+	}{
+		{"slimstar", loadSlimstar(ta)},
+		{"iplist", loadIPList(ta)[:1024*64]},
+	}
+
+	for _, ds := range datasets {
+		for _, mode := range buildModes {
+			b.Run(fmt.Sprintf("%s/%s", ds.name, mode), func(b *testing.B) {
+				var a *SlimArray
+				for i := 0; i < b.N; i++ {
+					a = NewU32Opt(ds.nums, &Opt{Mode: mode})
+				}
+				b.ReportMetric(float64(a.Stats().MemTotal*8)/float64(len(ds.nums)), "bits/elt")
+			})
+		}
+	}
+}
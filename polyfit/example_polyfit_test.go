@@ -57,3 +57,23 @@ func evalPoly(poly []float64, x float64) float64 {
 
 	return rst
 }
+
+func ExamplePolynomial() {
+
+	xs := []float64{1, 2, 3, 4}
+	ys := []float64{6, 5, 7, 7}
+
+	poly := NewFit(xs, ys, 2).Solve()
+	st := poly.Residuals(xs, ys)
+
+	fmt.Println(poly)
+	fmt.Printf("y(5) = %.1f\n", poly.Eval(5))
+	fmt.Println("derivative:", poly.Derivative())
+	fmt.Printf("residuals: max: %.1f, min: %.1f, rms: %.1f\n", st.Max, st.Min, st.RMS)
+
+	// Output:
+	// y = 6.25 - 0.75x + 0.25x²
+	// y(5) = 8.8
+	// derivative: y = -0.75 + 0.5x
+	// residuals: max: 0.8, min: -0.8, rms: 0.6
+}
@@ -0,0 +1,132 @@
+package polyfit
+
+import "math"
+
+const (
+	// Max exponent powerSums supports.
+	// XᵀX of degree d requires sum of x²ᵈ, thus NewFitIntRange computes XᵀX
+	// with closed form for degree up to 10.
+	maxPowerSumExp = 20
+)
+
+// bernoulli[k] is the k-th Bernoulli number, with B₁ = -1/2.
+var bernoulli = [maxPowerSumExp + 1]float64{
+	1, -1.0 / 2, 1.0 / 6, 0, -1.0 / 30, 0, 1.0 / 42, 0, -1.0 / 30, 0, 5.0 / 66,
+	0, -691.0 / 2730, 0, 7.0 / 6, 0, -3617.0 / 510, 0, 43867.0 / 798, 0, -174611.0 / 330,
+}
+
+// powerSum0 returns the sum of the p-th power of the first n non-negative
+// integers, with Faulhaber's formula:
+//
+//    n-1         1    p
+//     ∑  kᵖ  =  ---   ∑ C(p+1, j) Bⱼ nᵖ⁺¹⁻ʲ
+//    k=0        p+1  j=0
+//
+// Since 0.1.15
+func powerSum0(n, p int) float64 {
+
+	var npow [maxPowerSumExp + 2]float64
+	powers(float64(n), npow[:p+2])
+
+	return powerSum0Pow(npow[:], p)
+}
+
+// powerSum0Pow is the same as powerSum0, except the powers of n are given:
+// npow[i] = nⁱ.
+func powerSum0Pow(npow []float64, p int) float64 {
+
+	rst := float64(0)
+
+	// C(p+1, j)
+	c := float64(1)
+	for j := 0; j <= p; j++ {
+		if bernoulli[j] != 0 {
+			rst += c * bernoulli[j] * npow[p+1-j]
+		}
+		c = c * float64(p+1-j) / float64(j+1)
+	}
+
+	return roundInt(rst / float64(p+1))
+}
+
+// powerSums stores in sums[p] the sum of the p-th power of integers in range
+// [a, b), for p in [0, maxP].
+//
+// Sum of every power of integers in [a, a+n) is expanded into sums of
+// integers in [0, n), thus it does not subtract two large sums:
+//
+//    n-1              p              n-1
+//     ∑  (a+k)ᵖ  =    ∑ C(p,j) aᵖ⁻ʲ   ∑  kʲ
+//    k=0             j=0             k=0
+//
+// It costs O(maxP²).
+//
+// Since 0.1.15
+func powerSums(a, b, maxP int, sums []float64) {
+
+	if a < 0 {
+		// Expanding (a+k)ᵖ with negative a results in cancellation of large
+		// terms. Sum negative integers with (-x)ᵖ = (-1)ᵖxᵖ instead.
+		negEnd := b
+		if negEnd > 0 {
+			negEnd = 0
+		}
+
+		powerSums(1-negEnd, 1-a, maxP, sums)
+		for p := 1; p <= maxP; p += 2 {
+			sums[p] = -sums[p]
+		}
+
+		if b > 0 {
+			var pos [maxPowerSumExp + 1]float64
+			powerSums(0, b, maxP, pos[:])
+			for p := 0; p <= maxP; p++ {
+				sums[p] += pos[p]
+			}
+		}
+		return
+	}
+
+	var s0 [maxPowerSumExp + 1]float64
+	var npow [maxPowerSumExp + 2]float64
+	var apow [maxPowerSumExp + 1]float64
+
+	powers(float64(b-a), npow[:maxP+2])
+	powers(float64(a), apow[:maxP+1])
+
+	for j := 0; j <= maxP; j++ {
+		s0[j] = powerSum0Pow(npow[:], j)
+	}
+
+	for p := 0; p <= maxP; p++ {
+		rst := float64(0)
+
+		// C(p, j)
+		c := float64(1)
+		for j := 0; j <= p; j++ {
+			rst += c * apow[p-j] * s0[j]
+			c = c * float64(p-j) / float64(j+1)
+		}
+		sums[p] = roundInt(rst)
+	}
+}
+
+// powers stores xⁱ in rst[i].
+// It is exact if x is an integer and xⁱ < 2⁵³.
+func powers(x float64, rst []float64) {
+	v := float64(1)
+	for i := range rst {
+		rst[i] = v
+		v *= x
+	}
+}
+
+// roundInt rounds a value that is expected to be an integer, to remove
+// floating point error.
+// A value not less than 2⁵³ is already an integer in float64.
+func roundInt(v float64) float64 {
+	if math.Abs(v) < 1<<53 {
+		return math.Round(v)
+	}
+	return v
+}
@@ -0,0 +1,166 @@
+package polyfit
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPowerSum0(t *testing.T) {
+
+	ta := assert.New(t)
+
+	for p := 0; p <= 4; p++ {
+		want := float64(0)
+		for n := 0; n <= 1024; n++ {
+			ta.Equal(want, powerSum0(n, p), "n: %d, p: %d", n, p)
+			want += ipow(float64(n), p)
+		}
+	}
+
+	for p := 0; p <= maxPowerSumExp; p++ {
+		want := float64(0)
+		for n := 0; n <= 64; n++ {
+			ta.InDelta(want, powerSum0(n, p), math.Abs(want)*1e-12, "n: %d, p: %d", n, p)
+			want += ipow(float64(n), p)
+		}
+	}
+}
+
+func TestPowerSums(t *testing.T) {
+
+	ta := assert.New(t)
+
+	cases := []struct {
+		a, b int
+	}{
+		{0, 0},
+		{0, 1},
+		{5, 5},
+		{3, 7},
+		{-7, 3},
+		{-7, -3},
+		{1000, 1024},
+		{1 << 20, 1<<20 + 16},
+	}
+
+	sums := make([]float64, maxPowerSumExp+1)
+
+	for _, c := range cases {
+		powerSums(c.a, c.b, maxPowerSumExp, sums)
+
+		for p := 0; p <= maxPowerSumExp; p++ {
+			want, abs := float64(0), float64(0)
+			for x := c.a; x < c.b; x++ {
+				want += ipow(float64(x), p)
+				abs += math.Abs(ipow(float64(x), p))
+			}
+
+			if abs < 1<<53 {
+				ta.Equal(want, sums[p], "[%d, %d) p: %d", c.a, c.b, p)
+			} else {
+				ta.InDelta(want, sums[p], abs*1e-12, "[%d, %d) p: %d", c.a, c.b, p)
+			}
+		}
+	}
+}
+
+func TestNewIntRange(t *testing.T) {
+
+	ta := assert.New(t)
+
+	xs := make([]float64, 1024)
+	ys := make([]float64, 1024)
+	for i := 0; i < 1024; i++ {
+		xs[i] = float64(i)
+	}
+
+	for i := 0; i < 1024+1; i++ {
+		for j := i; j < i+5 && j < 1024+1; j++ {
+			{
+				// degree 2: every sum is an integer less than 2⁵³
+				f := NewFit(xs[i:j], ys[i:j], 2)
+				fint := NewFitIntRange(i, j, ys[i:j], 2)
+				ta.Equal(f, fint)
+			}
+
+			{
+				// degree 3: x⁶ may exceed 2⁵³. Sum of x⁶ is more accurate than
+				// adding x⁶ one by one.
+				f := NewFit(xs[i:j], ys[i:j], 3)
+				fint := NewFitIntRange(i, j, ys[i:j], 3)
+				ta.Equal(f.N, fint.N)
+				ta.Equal(f.xty, fint.xty)
+				for k, v := range f.xtx {
+					ta.InDelta(v, fint.xtx[k], math.Abs(v)*1e-15)
+				}
+			}
+
+			{
+				// degree beyond the closed form
+				f := NewFit(xs[i:j], ys[i:j], 11)
+				fint := NewFitIntRange(i, j, ys[i:j], 11)
+				ta.Equal(f, fint)
+			}
+		}
+	}
+}
+
+func BenchmarkNewFitIntRange(b *testing.B) {
+
+	ys := make([]float64, 16)
+
+	for _, degree := range []int{2, 5} {
+		b.Run(fmt.Sprintf("degree-%d", degree), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				NewFitIntRange(1000, 1016, ys, degree)
+			}
+		})
+	}
+}
+
+func ipow(x float64, e int) float64 {
+	rst := float64(1)
+	for i := 0; i < e; i++ {
+		rst *= x
+	}
+	return rst
+}
+
+func TestNewUnitFit(t *testing.T) {
+
+	ta := assert.New(t)
+
+	ys := []float64{3, 1, 4, 1, 5, 9, 2, 6}
+
+	cases := []struct {
+		x0, step float64
+		isRange  bool
+	}{
+		{0, 1, true},
+		{-3, 1, true},
+		{1000, 1, true},
+		{0.5, 1, false},
+		{0, 2, false},
+	}
+
+	for _, c := range cases {
+		xs := make([]float64, len(ys))
+		for i := range xs {
+			xs[i] = c.x0 + c.step*float64(i)
+		}
+
+		got := newUnitFit(xs, ys, 2)
+		want := NewFit(xs, ys, 2)
+
+		ta.Equal(want.N, got.N, "x0: %v, step: %v", c.x0, c.step)
+		ta.InDeltaSlice(want.xty, got.xty, 1e-6, "x0: %v, step: %v", c.x0, c.step)
+		ta.InDeltaSlice(want.xtx, got.xtx, 1e-6, "x0: %v, step: %v", c.x0, c.step)
+
+		if c.isRange {
+			ta.Equal(NewFitIntRange(int(c.x0), int(c.x0)+len(ys), ys, 2).xtx, got.xtx)
+		}
+	}
+}
@@ -0,0 +1,231 @@
+package polyfit
+
+import (
+	"math"
+	"sort"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+const (
+	// Max number of exchange iterations in Minimax.
+	// The exchange algorithm usually converges in a few iterations.
+	minimaxMaxIter = 64
+)
+
+// Minimax models a polynomial y from sample points xs and ys, to minimize the
+// max absolute residual, i.e., the Chebyshev(L∞) approximation:
+//
+//    minimize max |f(xᵢ) - yᵢ|
+//                i
+//
+// While least squares minimizes the squared residuals, a minimax polynomial
+// minimizes the range a residual could be in.
+//
+// It uses the single-point exchange algorithm(Remez/Stiefel):
+// It keeps a reference set of degree+2 points and solves the polynomial f and
+// h that the residuals alternate on the reference:
+//
+//    yᵢ - f(xᵢ) = (-1)ⁱ h
+//
+// Then it replaces one point in the reference with the point of the max
+// residual, until no residual is greater than |h|.
+// |h| increases in every iteration, thus it always converges.
+//
+// xs must be distinct. It does not need to be sorted.
+// It returns the coefficients of the polynomial and the max absolute residual.
+// It costs O(n) for every iteration and is meant for small sets of points.
+//
+// Since 0.1.15
+func Minimax(xs, ys []float64, degree int) (Polynomial, float64) {
+
+	n := len(xs)
+	m := degree + 1
+
+	if n == 0 {
+		return make(Polynomial, m), 0
+	}
+
+	idx := make([]int, n)
+	for i := range idx {
+		idx[i] = i
+	}
+	sort.Slice(idx, func(i, j int) bool { return xs[idx[i]] < xs[idx[j]] })
+
+	// Map x into [-1, 1] for a better conditioned linear system.
+	xmin, xmax := xs[idx[0]], xs[idx[n-1]]
+	c := (xmin + xmax) / 2
+	s := (xmax - xmin) / 2
+	if s == 0 {
+		s = 1
+	}
+
+	ts := make([]float64, n)
+	vs := make([]float64, n)
+	for i, j := range idx {
+		ts[i] = (xs[j] - c) / s
+		vs[i] = ys[j]
+	}
+
+	if n <= m {
+		// A polynomial of degree n-1 passes exactly through all points.
+		a := mat.NewDense(n, n, nil)
+		b := mat.NewDense(n, 1, nil)
+		for i := range ts {
+			v := float64(1)
+			for j := 0; j < n; j++ {
+				a.Set(i, j, v)
+				v *= ts[i]
+			}
+			b.Set(i, 0, vs[i])
+		}
+
+		var sol mat.Dense
+		_ = sol.Solve(a, b)
+
+		coef := make([]float64, m)
+		for j := 0; j < n; j++ {
+			coef[j] = sol.At(j, 0)
+		}
+
+		poly := unscale(coef, c, s)
+		return poly, maxAbsResidual(poly, xs, ys)
+	}
+
+	// Initial reference: degree+2 points evenly distributed.
+	ref := make([]int, m+1)
+	for k := range ref {
+		ref[k] = k * (n - 1) / m
+	}
+
+	coef := make([]float64, m)
+	var h float64
+
+	a := mat.NewDense(m+1, m+1, nil)
+	b := mat.NewDense(m+1, 1, nil)
+	var sol mat.Dense
+
+	for iter := 0; iter < minimaxMaxIter; iter++ {
+
+		for k, r := range ref {
+			v := float64(1)
+			for j := 0; j < m; j++ {
+				a.Set(k, j, v)
+				v *= ts[r]
+			}
+			a.Set(k, m, alternate(k))
+			b.Set(k, 0, vs[r])
+		}
+
+		err := sol.Solve(a, b)
+		if err != nil && iter > 0 {
+			// A near singular system does not give a better polynomial.
+			break
+		}
+
+		for j := 0; j < m; j++ {
+			coef[j] = sol.At(j, 0)
+		}
+		h = sol.At(m, 0)
+
+		worst, worstErr := 0, float64(0)
+		for i := range ts {
+			e := vs[i] - Polynomial(coef).Eval(ts[i])
+			if math.Abs(e) > math.Abs(worstErr) {
+				worst, worstErr = i, e
+			}
+		}
+
+		if math.Abs(worstErr) <= math.Abs(h)*(1+1e-9)+1e-9 {
+			break
+		}
+
+		exchange(ref, h, worst, worstErr)
+	}
+
+	poly := unscale(coef, c, s)
+	return poly, maxAbsResidual(poly, xs, ys)
+}
+
+// exchange replaces one point in the reference with the point i with residual
+// e, keeping residual signs alternate on the reference.
+func exchange(ref []int, h float64, i int, e float64) {
+
+	l := len(ref)
+	hsign := float64(1)
+	if h < 0 {
+		hsign = -1
+	}
+
+	// whether residual of point i has the same sign as the k-th reference
+	// point.
+	sameSign := func(k int) bool {
+		return (e > 0) == (alternate(k)*hsign > 0)
+	}
+
+	if i < ref[0] {
+		if sameSign(0) {
+			ref[0] = i
+		} else {
+			copy(ref[1:], ref[:l-1])
+			ref[0] = i
+		}
+		return
+	}
+
+	if i > ref[l-1] {
+		if sameSign(l - 1) {
+			ref[l-1] = i
+		} else {
+			copy(ref, ref[1:])
+			ref[l-1] = i
+		}
+		return
+	}
+
+	for k := 0; k < l-1; k++ {
+		if i >= ref[k] && i <= ref[k+1] {
+			if sameSign(k) {
+				ref[k] = i
+			} else {
+				ref[k+1] = i
+			}
+			return
+		}
+	}
+}
+
+// alternate returns (-1)ᵏ
+func alternate(k int) float64 {
+	if k%2 == 0 {
+		return 1
+	}
+	return -1
+}
+
+// unscale converts polynomial of t = (x-c)/s to polynomial of x, with:
+//
+//    tᵏ = s⁻ᵏ ∑ C(k,j) xʲ (-c)ᵏ⁻ʲ
+//             j
+func unscale(coef []float64, c, s float64) Polynomial {
+
+	m := len(coef)
+	poly := make(Polynomial, m)
+
+	for k := 0; k < m; k++ {
+		sk := coef[k] / math.Pow(s, float64(k))
+		binom := float64(1)
+		for j := 0; j <= k; j++ {
+			// binom is C(k, j)
+			poly[j] += sk * binom * math.Pow(-c, float64(k-j))
+			binom = binom * float64(k-j) / float64(j+1)
+		}
+	}
+
+	return poly
+}
+
+func maxAbsResidual(poly Polynomial, xs, ys []float64) float64 {
+	st := poly.Residuals(xs, ys)
+	return math.Max(st.Max, -st.Min)
+}
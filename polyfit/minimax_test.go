@@ -0,0 +1,95 @@
+package polyfit_test
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	. "github.com/openacid/slimarray/polyfit"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMinimax(t *testing.T) {
+
+	ta := assert.New(t)
+
+	cases := []struct {
+		xs, ys  []float64
+		degree  int
+		want    []float64
+		wantErr float64
+	}{
+		// fewer points than coefficients
+		{[]float64{}, []float64{}, 1, []float64{0, 0}, 0},
+		{[]float64{1}, []float64{3}, 2, []float64{3, 0, 0}, 0},
+		{[]float64{1, 2}, []float64{3, 5}, 2, []float64{1, 2, 0}, 0},
+		// middle of max and min
+		{[]float64{1, 2, 3, 4}, []float64{6, 5, 7, 10}, 0, []float64{7.5}, 2.5},
+		// y = x², the minimax line on [0, 1] is y = x - 1/8
+		{[]float64{0, 0.5, 1}, []float64{0, 0.25, 1}, 1, []float64{-0.125, 1}, 0.125},
+		{[]float64{1, 0, 0.5}, []float64{1, 0, 0.25}, 1, []float64{-0.125, 1}, 0.125},
+		// exact polynomial: y = 1 + 2x + 3x²
+		{[]float64{0, 1, 2, 3, 4}, []float64{1, 6, 17, 34, 57}, 2, []float64{1, 2, 3}, 0},
+	}
+
+	for i, c := range cases {
+		poly, maxErr := Minimax(c.xs, c.ys, c.degree)
+
+		ta.InDeltaSlice(c.want, poly, 0.0001, "%d-th: actual: %v", i+1, poly)
+		ta.InDelta(c.wantErr, maxErr, 0.0001, "%d-th", i+1)
+	}
+}
+
+func TestMinimax_notWorseThanLeastSquares(t *testing.T) {
+
+	ta := assert.New(t)
+
+	for seed := int64(0); seed < 100; seed++ {
+		rnd := rand.New(rand.NewSource(seed))
+
+		n := 3 + rnd.Intn(200)
+		start := rnd.Intn(1024 - n)
+
+		xs := make([]float64, n)
+		ys := make([]float64, n)
+		for i := range xs {
+			xs[i] = float64(start + i)
+			ys[i] = float64(i*i/16 + rnd.Intn(1000))
+		}
+
+		for degree := 0; degree <= 2; degree++ {
+			poly, maxErr := Minimax(xs, ys, degree)
+
+			// least squares, shifted to the middle of max and min residual.
+			lsq := NewFit(xs, ys, degree).Solve()
+			max, min := math.Inf(-1), math.Inf(1)
+			for i, x := range xs {
+				e := ys[i] - eval(lsq, x)
+				max = math.Max(max, e)
+				min = math.Min(min, e)
+			}
+			lsqErr := (max - min) / 2
+
+			ta.True(maxErr <= lsqErr+1e-6,
+				"seed: %d degree: %d minimax: %v least squares: %v", seed, degree, maxErr, lsqErr)
+
+			for i, x := range xs {
+				ta.InDelta(0, math.Abs(ys[i]-eval(poly, x)), maxErr+1e-6)
+			}
+		}
+	}
+}
+
+func BenchmarkMinimax(b *testing.B) {
+
+	xs := make([]float64, 256)
+	ys := make([]float64, 256)
+	for i := range xs {
+		xs[i] = float64(i)
+		ys[i] = float64(i*i/16 + rand.Intn(1000))
+	}
+
+	for i := 0; i < b.N; i++ {
+		Minimax(xs, ys, 2)
+	}
+}
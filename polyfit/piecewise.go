@@ -0,0 +1,235 @@
+package polyfit
+
+import (
+	"math"
+)
+
+// Piece is a polynomial that fits the points xs[Start:End] in Piecewise.
+//
+// Since 0.1.15
+type Piece struct {
+	// Start and End is the index range of points in this piece.
+	Start, End int
+
+	// Poly is the least squares polynomial of points in this piece.
+	Poly Polynomial
+
+	// Residuals describes the residuals of points in this piece with Poly.
+	Residuals ResidualStats
+}
+
+// CostFunc returns the cost of a piece.
+// Piecewise minimizes the total cost of all pieces.
+// A cost of +Inf forbids a piece.
+//
+// Since 0.1.15
+type CostFunc func(p *Piece) float64
+
+// MaxErrorCost returns a CostFunc that minimizes the number of pieces, with
+// every residual not greater than maxErr in absolute value.
+//
+// Since 0.1.15
+func MaxErrorCost(maxErr float64) CostFunc {
+	return func(p *Piece) float64 {
+		if p.Residuals.Max > maxErr || -p.Residuals.Min > maxErr {
+			return math.Inf(1)
+		}
+		return 1
+	}
+}
+
+// PenaltyCost returns a CostFunc that minimizes the sum of squared residuals
+// plus penalty for every piece.
+// A greater penalty results in fewer pieces.
+//
+// Since 0.1.15
+func PenaltyCost(penalty float64) CostFunc {
+	return func(p *Piece) float64 {
+		n := float64(p.End - p.Start)
+		return penalty + p.Residuals.RMS*p.Residuals.RMS*n
+	}
+}
+
+// BitsCost returns a CostFunc that minimizes the number of bits to store all
+// points: coefBits for every coefficient of a polynomial and the bits to
+// store every residual as an integer offset from the min residual.
+//
+// Since 0.1.15
+func BitsCost(coefBits int) CostFunc {
+	return func(p *Piece) float64 {
+		n := float64(p.End - p.Start)
+		margin := math.Ceil(p.Residuals.Max - p.Residuals.Min)
+		width := math.Ceil(math.Log2(margin + 1))
+		return float64(coefBits*len(p.Poly)) + width*n
+	}
+}
+
+// Piecewise splits the points into pieces and fits every piece with a
+// polynomial of the specified degree, to minimize the total cost of all
+// pieces. xs should be sorted.
+// It is the same as PiecewiseUnit() with unit = degree + 1.
+//
+// Since 0.1.15
+func Piecewise(xs, ys []float64, degree int, cost CostFunc) []Piece {
+	return PiecewiseUnit(xs, ys, degree, degree+1, cost)
+}
+
+// PiecewiseUnit splits the points into pieces of multiple of unit points, and
+// fits every piece with a polynomial of the specified degree, to minimize the
+// total cost of all pieces. The last piece may have less points.
+//
+// It starts with pieces of unit points, and greedily merges the two adjacent
+// pieces that reduce the most cost, until no merge reduces cost.
+// The fitting of a merged piece is merged from the fitting of the two pieces,
+// thus a merge costs O(n) for evaluating residuals.
+// If the xs of a unit are consecutive integers, its fitting is built with
+// NewFitIntRange.
+//
+// Since 0.1.15
+func PiecewiseUnit(xs, ys []float64, degree, unit int, cost CostFunc) []Piece {
+
+	n := len(xs)
+	if n == 0 {
+		return nil
+	}
+
+	if unit < 1 {
+		unit = 1
+	}
+
+	pw := &piecewise{
+		xs:   xs,
+		ys:   ys,
+		cost: cost,
+	}
+
+	pieces := make([]*piece, 0, (n+unit-1)/unit)
+	for s := 0; s < n; s += unit {
+		e := s + unit
+		if e > n {
+			e = n
+		}
+		pieces = append(pieces, pw.newPiece(newUnitFit(xs[s:e], ys[s:e], degree), s, e))
+	}
+
+	merged := make([]*piece, len(pieces)-1)
+	for i := range merged {
+		merged[i] = pw.merge(pieces[i], pieces[i+1])
+	}
+
+	for len(merged) > 0 {
+
+		// find the merge that reduces the most cost
+
+		maxReduced := math.Inf(-1)
+		maxI := 0
+
+		for i, m := range merged {
+			reduced := reducedCost(pieces[i], pieces[i+1], m)
+			if reduced > maxReduced {
+				maxI = i
+				maxReduced = reduced
+			}
+		}
+
+		if !(maxReduced > 0) {
+			// Even the best merge does not reduce cost.
+			break
+		}
+
+		// maxI -> b
+		//
+		// pieces:   a  b  c  d
+		// merged:    ab bc cd
+		//
+		// becomes:
+		//
+		// pieces:   a   bc   d
+		// merged:    abc  bcd
+
+		// ab + c => abc
+		if maxI > 0 {
+			merged[maxI-1] = pw.merge(merged[maxI-1], pieces[maxI+1])
+		}
+
+		// b + cd => bcd
+		if maxI < len(merged)-1 {
+			merged[maxI+1] = pw.merge(pieces[maxI], merged[maxI+1])
+		}
+
+		pieces[maxI] = merged[maxI]
+		pieces = append(pieces[:maxI+1], pieces[maxI+2:]...)
+		merged = append(merged[:maxI], merged[maxI+1:]...)
+	}
+
+	rst := make([]Piece, len(pieces))
+	for i, p := range pieces {
+		rst[i] = p.Piece
+	}
+	return rst
+}
+
+// newUnitFit creates a Fit of the points of a unit.
+// If xs are consecutive integers, such as indexes of an array, XᵀX is built
+// with NewFitIntRange in O(degree²).
+func newUnitFit(xs, ys []float64, degree int) *Fit {
+
+	x0 := xs[0]
+	isRange := x0 == math.Trunc(x0) && math.Abs(x0) < 1<<31
+	for i, x := range xs {
+		if x != x0+float64(i) {
+			isRange = false
+			break
+		}
+	}
+
+	if isRange {
+		return NewFitIntRange(int(x0), int(x0)+len(xs), ys, degree)
+	}
+	return NewFit(xs, ys, degree)
+}
+
+type piecewise struct {
+	xs, ys []float64
+	cost   CostFunc
+}
+
+// piece is a Piece with the fitting context and cost.
+type piece struct {
+	Piece
+	ft   *Fit
+	cost float64
+}
+
+func (pw *piecewise) newPiece(ft *Fit, s, e int) *piece {
+
+	poly := ft.Solve()
+
+	p := &piece{
+		Piece: Piece{
+			Start:     s,
+			End:       e,
+			Poly:      poly,
+			Residuals: poly.Residuals(pw.xs[s:e], pw.ys[s:e]),
+		},
+		ft: ft,
+	}
+	p.cost = pw.cost(&p.Piece)
+
+	return p
+}
+
+// merge creates a new piece of two adjacent pieces.
+func (pw *piecewise) merge(a, b *piece) *piece {
+	ft := a.ft.Copy()
+	ft.Merge(b.ft)
+	return pw.newPiece(ft, a.Start, b.End)
+}
+
+// reducedCost returns the cost reduced by merging a and b into m.
+func reducedCost(a, b, m *piece) float64 {
+	if math.IsInf(m.cost, 1) {
+		return math.Inf(-1)
+	}
+	return a.cost + b.cost - m.cost
+}
@@ -0,0 +1,146 @@
+package polyfit_test
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	. "github.com/openacid/slimarray/polyfit"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPiecewise_MaxErrorCost(t *testing.T) {
+
+	ta := assert.New(t)
+
+	// 3 lines: [0, 20), [20, 40), [40, 60)
+	xs := make([]float64, 60)
+	ys := make([]float64, 60)
+	for i := range xs {
+		x := float64(i)
+		xs[i] = x
+		switch {
+		case i < 20:
+			ys[i] = 2 * x
+		case i < 40:
+			ys[i] = 100 - x
+		default:
+			ys[i] = 3*x - 50
+		}
+	}
+
+	pieces := Piecewise(xs, ys, 1, MaxErrorCost(1e-6))
+	ta.Equal(3, len(pieces))
+
+	wants := []struct {
+		s, e int
+		poly Polynomial
+	}{
+		{0, 20, Polynomial{0, 2}},
+		{20, 40, Polynomial{100, -1}},
+		{40, 60, Polynomial{-50, 3}},
+	}
+
+	for i, w := range wants {
+		p := pieces[i]
+		ta.Equal(w.s, p.Start)
+		ta.Equal(w.e, p.End)
+		ta.InDeltaSlice(w.poly, p.Poly, 1e-6)
+		ta.InDelta(0, p.Residuals.RMS, 1e-6)
+	}
+
+	// with a loose bound every point is in one piece
+	pieces = Piecewise(xs, ys, 1, MaxErrorCost(1000))
+	ta.Equal(1, len(pieces))
+}
+
+func TestPiecewise_cost(t *testing.T) {
+
+	ta := assert.New(t)
+
+	rnd := rand.New(rand.NewSource(0))
+
+	xs := make([]float64, 1000)
+	ys := make([]float64, 1000)
+	v := 0
+	for i := range xs {
+		xs[i] = float64(i)
+		v += rnd.Intn(10)
+		if rnd.Intn(100) == 0 {
+			v += rnd.Intn(10000)
+		}
+		ys[i] = float64(v)
+	}
+
+	costFns := map[string]CostFunc{
+		"bits":    BitsCost(64),
+		"penalty": PenaltyCost(1000),
+		"error":   MaxErrorCost(50),
+	}
+
+	for name, cost := range costFns {
+		for _, unit := range []int{1, 3, 16} {
+
+			pieces := PiecewiseUnit(xs, ys, 2, unit, cost)
+
+			// pieces cover every point
+			ta.Equal(0, pieces[0].Start)
+			ta.Equal(len(xs), pieces[len(pieces)-1].End)
+
+			total := float64(0)
+			for i, p := range pieces {
+				if i > 0 {
+					ta.Equal(pieces[i-1].End, p.Start)
+				}
+				if i < len(pieces)-1 {
+					ta.Equal(0, (p.End-p.Start)%unit, "%s unit: %d", name, unit)
+				}
+
+				ta.Equal(p.Poly.Residuals(xs[p.Start:p.End], ys[p.Start:p.End]), p.Residuals)
+				total += cost(&p)
+			}
+
+			// Every merge reduces cost, thus it is not worse than unit pieces.
+			unitTotal := float64(0)
+			for s := 0; s < len(xs); s += unit {
+				e := s + unit
+				if e > len(xs) {
+					e = len(xs)
+				}
+				p := PiecewiseUnit(xs[s:e], ys[s:e], 2, unit, cost)[0]
+				unitTotal += cost(&p)
+			}
+			ta.True(total <= unitTotal, "%s unit: %d", name, unit)
+		}
+	}
+}
+
+func TestPiecewise_empty(t *testing.T) {
+
+	ta := assert.New(t)
+
+	ta.Nil(Piecewise(nil, nil, 2, BitsCost(64)))
+
+	pieces := Piecewise([]float64{1}, []float64{5}, 2, BitsCost(64))
+	ta.Equal([]Piece{{Start: 0, End: 1, Poly: Polynomial{5, 0, 0}}}, pieces)
+}
+
+func TestCostFuncs(t *testing.T) {
+
+	ta := assert.New(t)
+
+	p := &Piece{
+		Start:     10,
+		End:       20,
+		Poly:      Polynomial{1, 2, 3},
+		Residuals: ResidualStats{Max: 3, Min: -4, RMS: 2},
+	}
+
+	ta.Equal(math.Inf(1), MaxErrorCost(3)(p))
+	ta.Equal(float64(1), MaxErrorCost(4)(p))
+
+	ta.Equal(float64(100+4*10), PenaltyCost(100)(p))
+
+	// margin 7 requires 3 bits
+	ta.Equal(float64(64*3+3*10), BitsCost(64)(p))
+}
@@ -14,6 +14,7 @@ package polyfit
 
 import (
 	"fmt"
+	"math"
 	"strings"
 
 	"gonum.org/v1/gonum/mat"
@@ -56,77 +57,16 @@ type Fit struct {
 	N      int
 	Degree int
 
-	// cache XᵀX
+	// cache XᵀX, or XᵀWX for weighted points
 	xtx []float64
-	// cache XᵀY
+	// cache XᵀY, or XᵀWY for weighted points
 	xty []float64
 }
 
-var (
-	// XTXCache3 is the cache of XᵀX of degree 2 with integer matrix X = [[1,0,0], [1,1,1], [1,2,4]...].
-	// XTXCache3[l] is the cached XᵀX of X = [0..l], the first l integers.
-	XTXCache3 [1024 + 1][]float64
-
-	// PowCache caches x^i in PowCache[x][i].
-	PowCache [1024][5]float64
-)
-
-func init() {
-
-	initPowCache()
-
-	m := 3
-
-	XTXCache3[0] = make([]float64, m*m)
-	for i := 0; i < m*m; i++ {
-		XTXCache3[0][i] = 0
-	}
-
-	// |    1  |   |   X    |
-	// | Xᵀ i  | * | 1 i i² | = XᵀX + IᵀI
-	// |    i² |   |        |
-	//
-	//       | 1  i  i² |
-	// IᵀI = | i  i² i³ |
-	//       | i² i³ i⁴ |
-
-	for k := 0; k < 1024; k++ {
-		v := float64(k)
-		xPowers := []float64{1, v, v * v, v * v * v, v * v * v * v}
-
-		XTXCache3[k+1] = make([]float64, m*m)
-
-		for i := 0; i < m; i++ {
-			for j := 0; j < m; j++ {
-				XTXCache3[k+1][i*m+j] = XTXCache3[k][i*m+j] + xPowers[i+j]
-			}
-		}
-	}
-}
-
-func initPowCache() {
-	for i := 0; i < 1024; i++ {
-		v := float64(i)
-		PowCache[i][0] = 1
-		PowCache[i][1] = v
-		PowCache[i][2] = v * v
-		PowCache[i][3] = v * v * v
-		PowCache[i][4] = v * v * v * v
-	}
-
-}
-
-// getCachedXTX3 retrieves a cached 3*3 matrix of Xᵀ * X, where X = [[1, a, a^2], [1,(a+1),(a+1)^2]...]
-func getCachedXTX3(a, b int, rst []float64) {
-	m := 3
-	for i := 0; i < m*m; i++ {
-		rst[i] = XTXCache3[b][i] - XTXCache3[a][i]
-	}
-}
-
 // NewFitIntRange is similar to NewFit, except
-// it only accept integer value for x, and the value of x must be in range
-// [0, 1024). Integer is optimized by caching XᵀX values.
+// it only accept integer value for x: xStart, xStart+1 ... xEnd-1.
+// Integer is optimized by computing XᵀX with closed form formula of sum of
+// powers, in O(degree²) instead of O(n * degree²).
 //
 // And the input must satisfies:
 //   len(ys) == xEnd - xStart
@@ -146,31 +86,33 @@ func NewFitIntRange(xStart, xEnd int, ys []float64, degree int) *Fit {
 		xty: make([]float64, m),
 	}
 
-	for i := 0; i < m; i++ {
-		f.xty[i] = 0
-	}
-
-	if m == 3 {
-		getCachedXTX3(xStart, xEnd, f.xtx)
-
+	if 2*degree > maxPowerSumExp {
 		for i := 0; i < n; i++ {
-			for j := 0; j < m; j++ {
-				f.xty[j] += PowCache[xStart+i][j] * ys[i]
-			}
+			f.Add(float64(xStart+i), ys[i])
 		}
+		return f
+	}
 
-		f.N = n
-	} else {
-		for i := 0; i < m*m; i++ {
-			f.xtx[i] = 0
-		}
+	var sums [maxPowerSumExp + 1]float64
+	powerSums(xStart, xEnd, 2*degree, sums[:])
 
-		for i := 0; i < n; i++ {
-			f.Add(float64(xStart+i), ys[i])
+	for i := 0; i < m; i++ {
+		for j := 0; j < m; j++ {
+			f.xtx[i*m+j] = sums[i+j]
 		}
+	}
 
+	for i := 0; i < n; i++ {
+		x := float64(xStart + i)
+		v := float64(1)
+		for j := 0; j < m; j++ {
+			f.xty[j] += v * ys[i]
+			v *= x
+		}
 	}
 
+	f.N = n
+
 	return f
 }
 
@@ -207,6 +149,22 @@ func NewFit(xs, ys []float64, degree int) *Fit {
 	return f
 }
 
+// NewFitWeighted creates a new polynomial fitting context, with points, the
+// weight of every point and the degree of the polynomial.
+// The result polynomial minimizes the weighted squared residuals.
+//
+// Since 0.1.15
+func NewFitWeighted(xs, ys, ws []float64, degree int) *Fit {
+
+	f := NewFit(nil, nil, degree)
+
+	for i := range xs {
+		f.AddWeighted(xs[i], ys[i], ws[i])
+	}
+
+	return f
+}
+
 // Copy into a new instance.
 //
 // Since 0.1.3
@@ -229,10 +187,48 @@ func (f *Fit) Copy() *Fit {
 //
 // Since 0.1.0
 func (f *Fit) Add(x, y float64) {
+	f.AddWeighted(x, y, 1)
+}
+
+// AddWeighted adds a point(x, y) with weight w into this fitting.
+// A point with weight w contributes to the squared residuals as w points.
+// Adding a point with weight 1 is the same as Add().
+//
+// Weighted least squares minimizes ∑ wᵢ(f(xᵢ) - yᵢ)², thus:
+//    β = (XᵀWX)⁻¹XᵀWY
+//
+// where W is the diagonal matrix of weights.
+//
+// Since 0.1.15
+func (f *Fit) AddWeighted(x, y, w float64) {
+	f.accumulate(x, y, w)
+	f.N++
+}
+
+// Remove a point(x, y) from this fitting.
+// The point must have been added before.
+//
+// Since 0.1.15
+func (f *Fit) Remove(x, y float64) {
+	f.accumulate(x, y, -1)
+	f.N--
+}
+
+// accumulate adds w times the contribution of point(x, y) to XᵀX and XᵀY.
+// It does not change N.
+func (f *Fit) accumulate(x, y, w float64) {
 
 	m := f.Degree + 1
 
-	xPowers := make([]float64, m)
+	// avoid allocation for small degree
+	var buf [8]float64
+	var xPowers []float64
+	if m <= len(buf) {
+		xPowers = buf[:m]
+	} else {
+		xPowers = make([]float64, m)
+	}
+
 	v := float64(1)
 	for i := 0; i < m; i++ {
 		xPowers[i] = v
@@ -241,15 +237,13 @@ func (f *Fit) Add(x, y float64) {
 
 	for i := 0; i < m; i++ {
 		for j := 0; j < m; j++ {
-			f.xtx[i*m+j] += xPowers[i] * xPowers[j]
+			f.xtx[i*m+j] += w * xPowers[i] * xPowers[j]
 		}
 	}
 
 	for i := 0; i < m; i++ {
-		f.xty[i] += xPowers[i] * y
+		f.xty[i] += w * xPowers[i] * y
 	}
-
-	f.N++
 }
 
 // Merge two sets of sample data.
@@ -278,28 +272,62 @@ func (f *Fit) Merge(b *Fit) {
 	}
 }
 
+// Sub removes a set of sample data b, which is a subset of f.
+// It is the reverse operation of Merge.
+//
+// This can be done because:
+//
+//    X₂ᵀX₂ = |X₁|ᵀ × |X₁| - X₁ᵀX₁
+//            |X₂|    |X₂|
+//
+// Since 0.1.15
+func (f *Fit) Sub(b *Fit) {
+
+	if f.Degree != b.Degree {
+		panic(fmt.Sprintf("different degree: %d %d", f.Degree, b.Degree))
+	}
+
+	f.N -= b.N
+
+	m := f.Degree + 1
+
+	for i := 0; i < m; i++ {
+		f.xty[i] -= b.xty[i]
+		for j := 0; j < m; j++ {
+			f.xtx[i*m+j] -= b.xtx[i*m+j]
+		}
+	}
+}
+
 // Solve the equation and returns coefficients of the result polynomial.
 // The number of coefficients is f.Degree + 1.
 //
 // It tries to reduce degree of the result polynomial. Since there is a
 // polynomial of degree n that passes exactly n+1 points.
 //
+// The result is inaccurate if XᵀX is ill-conditioned, e.g., with large x or
+// high degree. Use Cond() to check it, or use FitQR() instead.
+//
 // Since 0.1.0
-func (f *Fit) Solve() []float64 {
+func (f *Fit) Solve() Polynomial {
 
 	m := f.Degree + 1
 
-	if m <= f.N {
+	if m <= f.N && m <= 3 {
 		// quick path
-		rst := make([]float64, m)
+		rst := make(Polynomial, m)
 		if m == 1 {
 			solve1(f.xtx, f.xty, rst)
-			return rst
 		} else if m == 2 {
 			solve2(f.xtx, f.xty, rst)
-			return rst
-		} else if m == 3 {
+		} else {
 			solve3(f.xtx, f.xty, rst)
+		}
+
+		// The determinant may be 0 because of loss of precision, e.g., fit
+		// 9 points with x in [992, 1001).
+		// In this case fall back to the slow path.
+		if isFinite(rst) {
 			return rst
 		}
 	}
@@ -317,16 +345,17 @@ func (f *Fit) Solve() []float64 {
 
 	var beta mat.Dense
 	err := beta.Solve(coef, right)
+	if err != nil {
+		// It returns error about a large condition number, e.g.: matrix
+		// singular or near-singular with condition number 1.3240e+16.
+		// The β by LU decomposition is inaccurate or even NaN in this case.
+		// E.g., fit y = ax² + bx + c with points on a vertical line.
+		// Solve it again with SVD, ignoring the singular values that are lost
+		// in rounding error. It finds the least squares β with minimal norm.
+		solveSVD(coef, right, &beta)
+	}
 
-	// Sometimes it returns error about a large condition number, e.g.: matrix
-	// singular or near-singular with condition number 1.3240e+16.
-	// The β is inaccurate in this case(near singular matrix) but it does not
-	// matter. The most common case having this error is to fit points less
-	// than degree+1, e.g., fit y = ax² + bx + c with only two points, or with
-	// several points on a straight line.
-	_ = err
-
-	rst := make([]float64, f.Degree+1)
+	rst := make(Polynomial, f.Degree+1)
 	for i := 0; i < m; i++ {
 		rst[i] = beta.At(i, 0)
 	}
@@ -337,6 +366,36 @@ func (f *Fit) Solve() []float64 {
 	return rst
 }
 
+// svdRcond is the relative singular value below which a singular value of
+// XᵀX is treated as 0.
+const svdRcond = 1e-15
+
+// solveSVD solves the least squares problem coef * β = right with SVD.
+func solveSVD(coef, right mat.Matrix, beta *mat.Dense) {
+
+	var svd mat.SVD
+	if !svd.Factorize(coef, mat.SVDThin) {
+		return
+	}
+
+	rank := svd.Rank(svdRcond)
+	if rank == 0 {
+		beta.Zero()
+		return
+	}
+
+	svd.SolveTo(beta, right, rank)
+}
+
+func isFinite(vs []float64) bool {
+	for _, v := range vs {
+		if math.IsInf(v, 0) || math.IsNaN(v) {
+			return false
+		}
+	}
+	return true
+}
+
 func determinant2(v []float64) float64 {
 	a, b, c, d := v[0], v[1], v[2], v[3]
 	return a*d - b*c
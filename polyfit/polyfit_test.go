@@ -1,6 +1,7 @@
 package polyfit_test
 
 import (
+	"math/rand"
 	"testing"
 
 	. "github.com/openacid/slimarray/polyfit"
@@ -95,6 +96,148 @@ func TestFitting_Merge(t *testing.T) {
 	ta.Panics(func() { f.Merge(NewFit(xs[:2], ys[:2], 4)) })
 }
 
+func TestFitting_AddWeighted(t *testing.T) {
+
+	ta := assert.New(t)
+
+	xs := []float64{1, 2, 3, 4}
+	ys := []float64{6, 5, 7, 10}
+
+	// weight 1 is the same as Add
+	f := NewFitWeighted(xs, ys, []float64{1, 1, 1, 1}, 2)
+	ta.Equal(NewFit(xs, ys, 2), f)
+
+	// weight 2 is the same as adding a point twice
+	f = NewFitWeighted(xs, ys, []float64{1, 2, 1, 2}, 2)
+	g := NewFit(
+		[]float64{1, 2, 2, 3, 4, 4},
+		[]float64{6, 5, 5, 7, 10, 10}, 2)
+	ta.Equal(4, f.N)
+	ta.InDeltaSlice(g.Solve(), f.Solve(), 0.0001)
+
+	// weight 0 ignores a point
+	f = NewFitWeighted(xs, ys, []float64{1, 0, 1, 1}, 1)
+	g = NewFit([]float64{1, 3, 4}, []float64{6, 7, 10}, 1)
+	ta.InDeltaSlice(g.Solve(), f.Solve(), 0.0001)
+
+	// a heavy point pulls the line to it
+	f = NewFitWeighted(xs, ys, []float64{1, 1000, 1, 1}, 1)
+	ta.InDelta(5, eval(f.Solve(), 2), 0.01)
+}
+
+func TestFitting_MergeWeighted(t *testing.T) {
+
+	ta := assert.New(t)
+
+	xs := []float64{1, 2, 3, 4}
+	ys := []float64{6, 5, 7, 10}
+	ws := []float64{0.5, 2, 1, 3}
+
+	for degree := 0; degree < 4; degree++ {
+		f := NewFitWeighted(xs, ys, ws, degree)
+
+		fa := NewFitWeighted(xs[:2], ys[:2], ws[:2], degree)
+		fb := NewFitWeighted(xs[2:], ys[2:], ws[2:], degree)
+		fa.Merge(fb)
+
+		ta.Equal(f.String(), fa.String())
+		ta.InDeltaSlice(f.Solve(), fa.Solve(), 0.0001)
+	}
+}
+
+func TestFitting_Remove(t *testing.T) {
+
+	ta := assert.New(t)
+
+	xs := []float64{1, 2, 3, 4}
+	ys := []float64{6, 5, 7, 10}
+
+	f := NewFit(xs, ys, 2)
+
+	f.Remove(xs[3], ys[3])
+	ta.Equal(3, f.N)
+	ta.Equal(NewFit(xs[:3], ys[:3], 2).String(), f.String())
+
+	f.Remove(xs[0], ys[0])
+	ta.Equal(2, f.N)
+	ta.Equal(NewFit(xs[1:3], ys[1:3], 2).String(), f.String())
+}
+
+func TestFitting_Sub(t *testing.T) {
+
+	ta := assert.New(t)
+
+	xs := []float64{1, 2, 3, 4}
+	ys := []float64{6, 5, 7, 10}
+
+	f := NewFit(xs, ys, 3)
+	f.Sub(NewFit(xs[:2], ys[:2], 3))
+
+	ta.Equal(2, f.N)
+	ta.Equal(NewFit(xs[2:], ys[2:], 3).String(), f.String())
+	ta.InDeltaSlice(NewFit(xs[2:], ys[2:], 3).Solve(), f.Solve(), 0.0001)
+
+	// panic if degree differs
+
+	ta.Panics(func() { f.Sub(NewFit(xs[:2], ys[:2], 4)) })
+}
+
+func TestFitting_slidingWindow(t *testing.T) {
+
+	ta := assert.New(t)
+
+	rnd := rand.New(rand.NewSource(0))
+
+	n := 1024
+	window := 64
+
+	xs := make([]float64, n)
+	ys := make([]float64, n)
+	for i := range xs {
+		xs[i] = float64(i)
+		ys[i] = float64(i*5) + rnd.Float64()*1000
+	}
+
+	// Slide the window with Remove and Add.
+	f := NewFit(xs[:window], ys[:window], 2)
+
+	// Slide the window by one span of 16 points with Sub and Merge.
+	g := NewFit(xs[:window], ys[:window], 2)
+
+	for s := 1; s+window <= n; s++ {
+		e := s + window
+
+		f.Remove(xs[s-1], ys[s-1])
+		f.Add(xs[e-1], ys[e-1])
+
+		if s%16 == 0 {
+			g.Sub(NewFit(xs[s-16:s], ys[s-16:s], 2))
+			g.Merge(NewFit(xs[e-16:e], ys[e-16:e], 2))
+		}
+
+		if s%64 != 0 {
+			continue
+		}
+
+		want := NewFit(xs[s:e], ys[s:e], 2).Solve()
+
+		// Compare the evaluated values instead of coefficients: coefficients of
+		// a near singular system are unstable but the curve is not.
+		// The difference comes mostly from solving the ill-conditioned XᵀX:
+		// adding the same points in another order results in a similar
+		// difference. It is less than 0.5, the precision slimarray requires.
+		for _, fit := range []*Fit{f, g} {
+			ta.Equal(window, fit.N)
+
+			got := fit.Solve()
+			for i := s; i < e; i++ {
+				ta.InDelta(eval(want, xs[i]), eval(got, xs[i]), 0.5,
+					"window: [%d, %d), x: %d", s, e, i)
+			}
+		}
+	}
+}
+
 func TestFitting_Copy(t *testing.T) {
 
 	ta := assert.New(t)
@@ -155,6 +298,23 @@ func TestFitting_Solve(t *testing.T) {
 	}
 }
 
+func TestFitting_Solve_lossOfPrecision(t *testing.T) {
+
+	ta := assert.New(t)
+
+	// determinant of XᵀX is 0 with Cramer's rule.
+	xs := []float64{992, 993, 994, 995, 996, 997, 998, 999, 1000}
+	ys := []float64{7008, 7014, 7023, 7031, 7040, 7046, 7053, 7061, 7069}
+
+	f := NewFit(xs, ys, 2)
+	poly := f.Solve()
+
+	for j, x := range xs {
+		v := eval(poly, x)
+		ta.InDelta(ys[j], v, 2)
+	}
+}
+
 var Output int
 
 func BenchmarkFitting_Solve1(b *testing.B) {
@@ -205,6 +365,18 @@ func BenchmarkFitting_Solve3(b *testing.B) {
 	Output = s
 }
 
+func BenchmarkFitting_Add(b *testing.B) {
+
+	f := NewFit(nil, nil, 2)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		f.Add(float64(i&1023), float64(i))
+	}
+
+	Output = f.N
+}
+
 func eval(poly []float64, x float64) float64 {
 	rst := float64(0)
 	pow := float64(1)
@@ -215,3 +387,39 @@ func eval(poly []float64, x float64) float64 {
 
 	return rst
 }
+
+func TestFitting_Solve_singular(t *testing.T) {
+
+	ta := assert.New(t)
+
+	cases := []struct {
+		xs, ys []float64
+		degree int
+	}{
+		// points on a vertical line
+		{[]float64{2, 2, 2}, []float64{1, 2, 3}, 1},
+		{[]float64{2, 2, 2, 2}, []float64{1, 2, 3, 6}, 2},
+		// a duplicated point: 2 distinct points for a degree 2 polynomial
+		{[]float64{1, 3, 3}, []float64{5, 7, 7}, 2},
+	}
+
+	for i, c := range cases {
+		poly := NewFit(c.xs, c.ys, c.degree).Solve()
+		ta.Equal(c.degree+1, len(poly))
+
+		// The curve passes the mean of y at every distinct x.
+		sums := map[float64][]float64{}
+		for j, x := range c.xs {
+			sums[x] = append(sums[x], c.ys[j])
+		}
+		for x, ys := range sums {
+			mean := float64(0)
+			for _, y := range ys {
+				mean += y
+			}
+			mean /= float64(len(ys))
+
+			ta.InDelta(mean, eval(poly, x), 1e-6, "%d-th: x: %v, poly: %v", i+1, x, poly)
+		}
+	}
+}
@@ -0,0 +1,162 @@
+package polyfit
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Polynomial is the coefficients of a polynomial:
+//
+//    f(x) = p[0] + p[1]x + p[2]x² + ...
+//
+// Since 0.1.15
+type Polynomial []float64
+
+// ResidualStats describes the residuals yᵢ - f(xᵢ) of a polynomial f on a set
+// of points.
+//
+// Since 0.1.15
+type ResidualStats struct {
+	// Max is the max residual. It is 0 if every residual is negative.
+	Max float64
+	// Min is the min residual. It is 0 if every residual is positive.
+	Min float64
+	// RMS is the root mean square of residuals.
+	RMS float64
+}
+
+// Eval evaluates the polynomial at x.
+//
+// Since 0.1.15
+func (p Polynomial) Eval(x float64) float64 {
+
+	if len(p) == 3 {
+		// quick path for degree 2, the same as the loop below.
+		return p[0] + x*p[1] + x*x*p[2]
+	}
+
+	rst := float64(0)
+	pow := float64(1)
+	for _, c := range p {
+		rst += c * pow
+		pow *= x
+	}
+	return rst
+}
+
+// EvalInt evaluates the polynomial at integer x and truncates the result to
+// an integer.
+//
+// Since 0.1.15
+func (p Polynomial) EvalInt(x int64) int64 {
+	return int64(p.Eval(float64(x)))
+}
+
+// Derivative returns the derivative of the polynomial, which has one less
+// coefficient. The derivative of a constant is 0.
+//
+// Since 0.1.15
+func (p Polynomial) Derivative() Polynomial {
+
+	if len(p) <= 1 {
+		return Polynomial{0}
+	}
+
+	d := make(Polynomial, len(p)-1)
+	for i := 1; i < len(p); i++ {
+		d[i-1] = float64(i) * p[i]
+	}
+	return d
+}
+
+// Residuals returns the max, min and the root mean square of the residuals
+// yᵢ - f(xᵢ).
+//
+// Since 0.1.15
+func (p Polynomial) Residuals(xs, ys []float64) ResidualStats {
+
+	var st ResidualStats
+
+	if len(xs) == 0 {
+		return st
+	}
+
+	sum := float64(0)
+	for i, x := range xs {
+		d := ys[i] - p.Eval(x)
+		if d > st.Max {
+			st.Max = d
+		}
+		if d < st.Min {
+			st.Min = d
+		}
+		sum += d * d
+	}
+
+	st.RMS = math.Sqrt(sum / float64(len(xs)))
+
+	return st
+}
+
+// String converts the polynomial into human readable format, with zero terms
+// omitted, e.g.:
+//
+//    y = 6.2 - 0.8x + 0.2x²
+//
+// Since 0.1.15
+func (p Polynomial) String() string {
+
+	var ss []string
+
+	for i, c := range p {
+		if c == 0 {
+			continue
+		}
+
+		sign := "+"
+		if c < 0 {
+			sign = "-"
+			c = -c
+		}
+
+		var term string
+		if i > 0 && c == 1 {
+			term = "x" + superscript(i)
+		} else if i > 0 {
+			term = fmt.Sprintf("%.6gx%s", c, superscript(i))
+		} else {
+			term = fmt.Sprintf("%.6g", c)
+		}
+
+		if len(ss) == 0 {
+			if sign == "-" {
+				term = "-" + term
+			}
+			ss = append(ss, term)
+		} else {
+			ss = append(ss, sign, term)
+		}
+	}
+
+	if len(ss) == 0 {
+		return "y = 0"
+	}
+
+	return "y = " + strings.Join(ss, " ")
+}
+
+var superscriptDigits = []string{"⁰", "¹", "²", "³", "⁴", "⁵", "⁶", "⁷", "⁸", "⁹"}
+
+// superscript returns the exponent of xⁱ. x¹ is just x.
+func superscript(i int) string {
+	if i <= 1 {
+		return ""
+	}
+
+	var s string
+	for ; i > 0; i /= 10 {
+		s = superscriptDigits[i%10] + s
+	}
+	return s
+}
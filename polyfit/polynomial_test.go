@@ -0,0 +1,121 @@
+package polyfit_test
+
+import (
+	"math"
+	"testing"
+
+	. "github.com/openacid/slimarray/polyfit"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPolynomial_Eval(t *testing.T) {
+
+	ta := assert.New(t)
+
+	cases := []struct {
+		poly Polynomial
+		x    float64
+		want float64
+	}{
+		{Polynomial{}, 3, 0},
+		{Polynomial{5}, 3, 5},
+		{Polynomial{5, 2}, 3, 11},
+		{Polynomial{5, 2, 1}, 3, 20},
+		{Polynomial{5, 2, 1, -1}, 3, -7},
+		{Polynomial{5, 2, 1, -1}, -2, 13},
+	}
+
+	for i, c := range cases {
+		ta.Equal(c.want, c.poly.Eval(c.x), "%d-th: %v", i+1, c.poly)
+		ta.Equal(int64(c.want), c.poly.EvalInt(int64(c.x)), "%d-th: %v", i+1, c.poly)
+	}
+
+	// EvalInt truncates toward zero
+	ta.Equal(int64(2), Polynomial{2.9}.EvalInt(0))
+	ta.Equal(int64(-2), Polynomial{-2.9}.EvalInt(0))
+
+	// The quick path for degree 2 is the same as the general one.
+	p := Polynomial{0.1, 0.7, 0.3}
+	p4 := Polynomial{0.1, 0.7, 0.3, 0}
+	for x := float64(0); x < 1024; x++ {
+		ta.Equal(p4.Eval(x), p.Eval(x))
+	}
+}
+
+func TestPolynomial_Derivative(t *testing.T) {
+
+	ta := assert.New(t)
+
+	cases := []struct {
+		poly Polynomial
+		want Polynomial
+	}{
+		{Polynomial{}, Polynomial{0}},
+		{Polynomial{5}, Polynomial{0}},
+		{Polynomial{5, 2}, Polynomial{2}},
+		{Polynomial{5, 2, 3}, Polynomial{2, 6}},
+		{Polynomial{5, 2, 3, -1}, Polynomial{2, 6, -3}},
+	}
+
+	for i, c := range cases {
+		ta.Equal(c.want, c.poly.Derivative(), "%d-th: %v", i+1, c.poly)
+	}
+}
+
+func TestPolynomial_Residuals(t *testing.T) {
+
+	ta := assert.New(t)
+
+	p := Polynomial{1, 1}
+
+	ta.Equal(ResidualStats{}, p.Residuals(nil, nil))
+
+	xs := []float64{0, 1, 2, 3}
+	ys := []float64{1, 4, 2, 3}
+
+	// residuals: 0, 2, -1, -1
+	st := p.Residuals(xs, ys)
+	ta.Equal(float64(2), st.Max)
+	ta.Equal(float64(-1), st.Min)
+	ta.InDelta(math.Sqrt(6.0/4), st.RMS, 1e-12)
+
+	// residuals: 1, 1
+	st = Polynomial{0}.Residuals([]float64{0, 1}, []float64{1, 1})
+	ta.Equal(ResidualStats{Max: 1, Min: 0, RMS: 1}, st)
+}
+
+func TestPolynomial_String(t *testing.T) {
+
+	ta := assert.New(t)
+
+	cases := []struct {
+		poly Polynomial
+		want string
+	}{
+		{Polynomial{}, "y = 0"},
+		{Polynomial{0, 0}, "y = 0"},
+		{Polynomial{5}, "y = 5"},
+		{Polynomial{-5}, "y = -5"},
+		{Polynomial{0, 16}, "y = 16x"},
+		{Polynomial{-1, 1}, "y = -1 + x"},
+		{Polynomial{6.2, -0.8, 0.2}, "y = 6.2 - 0.8x + 0.2x²"},
+		{Polynomial{0, -1, 0, 2.5}, "y = -x + 2.5x³"},
+		{Polynomial{1, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 1.5e10}, "y = 1 + 1.5e+10x¹¹"},
+	}
+
+	for i, c := range cases {
+		ta.Equal(c.want, c.poly.String(), "%d-th: %#v", i+1, c.poly)
+	}
+}
+
+func BenchmarkPolynomial_Eval(b *testing.B) {
+
+	p := Polynomial{1, 2, 3}
+	s := float64(0)
+
+	for i := 0; i < b.N; i++ {
+		s += p.Eval(float64(i & 1023))
+	}
+
+	Output = int(s)
+}
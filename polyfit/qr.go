@@ -0,0 +1,116 @@
+package polyfit
+
+import (
+	"errors"
+	"fmt"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+var (
+	// IllConditioned is returned if the equations to solve is singular or near
+	// singular. The result polynomial may be inaccurate.
+	IllConditioned = errors.New("ill-conditioned equations")
+)
+
+// FitQR models a polynomial y from sample points xs and ys, to minimize the
+// squared residuals, the same as NewFit(xs, ys, degree).Solve().
+// But it is numerically stable for high degree or large x.
+//
+// Solve() solves the normal equations XᵀXβ = XᵀY.
+// The condition number of XᵀX is the square of that of X, and it grows fast
+// with x and the degree, e.g., XᵀX has x⁴ terms for degree 2.
+//
+// FitQR maps x into t = (x-c)/s in [-1, 1] and factorizes X of t into Q and R,
+// then solves Rβ = QᵀY with back substitution. Finally it converts β back to
+// coefficients of x.
+//
+// It returns the coefficients, the condition number estimate of X of t, and
+// an error wrapping IllConditioned if X of t is singular or near singular.
+//
+// If there are less than degree+1 points, it finds the polynomial of lower
+// degree that passes exactly through all points, as Solve() does.
+//
+// Since 0.1.15
+func FitQR(xs, ys []float64, degree int) (Polynomial, float64, error) {
+
+	n := len(xs)
+	m := degree + 1
+
+	if n == 0 {
+		return make(Polynomial, m), 0, nil
+	}
+
+	k := m
+	if n < k {
+		k = n
+	}
+
+	xmin, xmax := xs[0], xs[0]
+	for _, x := range xs {
+		if x < xmin {
+			xmin = x
+		}
+		if x > xmax {
+			xmax = x
+		}
+	}
+
+	c := (xmin + xmax) / 2
+	s := (xmax - xmin) / 2
+	if s == 0 {
+		s = 1
+	}
+
+	a := mat.NewDense(n, k, nil)
+	b := mat.NewDense(n, 1, nil)
+	for i, x := range xs {
+		t := (x - c) / s
+		v := float64(1)
+		for j := 0; j < k; j++ {
+			a.Set(i, j, v)
+			v *= t
+		}
+		b.Set(i, 0, ys[i])
+	}
+
+	var qr mat.QR
+	qr.Factorize(a)
+
+	var beta mat.Dense
+	err := qr.SolveTo(&beta, false, b)
+	cond := qr.Cond()
+
+	coef := make([]float64, m)
+	for j := 0; j < k; j++ {
+		coef[j] = beta.At(j, 0)
+	}
+
+	poly := unscale(coef, c, s)
+
+	if err != nil {
+		return poly, cond, fmt.Errorf("%w: %s", IllConditioned, err.Error())
+	}
+
+	return poly, cond, nil
+}
+
+// Cond returns the condition number estimate of XᵀX, the coefficient matrix
+// of the normal equations Solve() solves.
+// The result of Solve() loses about log₁₀(Cond()) significant decimal digits.
+// Use FitQR() if it is too large.
+//
+// Since 0.1.15
+func (f *Fit) Cond() float64 {
+
+	m := f.Degree + 1
+	if m > f.N {
+		m = f.N
+	}
+	if m == 0 {
+		return 0
+	}
+
+	xtx := mat.NewDense(f.Degree+1, f.Degree+1, f.xtx)
+	return mat.Cond(xtx.Slice(0, m, 0, m), 2)
+}
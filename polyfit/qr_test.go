@@ -0,0 +1,109 @@
+package polyfit_test
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+
+	. "github.com/openacid/slimarray/polyfit"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFitQR(t *testing.T) {
+
+	ta := assert.New(t)
+
+	xs := []float64{1, 2, 3, 4}
+	ys := []float64{6, 5, 7, 10}
+
+	cases := []struct {
+		degree int
+		want   []float64
+	}{
+		{0, []float64{7}},
+		{1, []float64{3.5, 1.4}},
+		{2, []float64{8.5, -3.6, 1}},
+		{3, []float64{12, -9.1666666, 3.5, -0.33333}},
+		{4, []float64{12, -9.1666666, 3.5, -0.33333, 0}},
+		{5, []float64{12, -9.1666666, 3.5, -0.33333, 0, 0}},
+	}
+
+	for i, c := range cases {
+		poly, cond, err := FitQR(xs, ys, c.degree)
+		ta.NoError(err)
+		ta.True(cond >= 1)
+
+		ta.InDeltaSlice(c.want, poly, 0.0001,
+			"%d-th: degree: %d; want: %#v; actual: %#v",
+			i+1, c.degree, c.want, poly)
+	}
+
+	// no point
+	poly, _, err := FitQR(nil, nil, 2)
+	ta.NoError(err)
+	ta.Equal(Polynomial{0, 0, 0}, poly)
+
+	// a single x can not determine a line
+	_, _, err = FitQR([]float64{3, 3, 3}, []float64{1, 2, 3}, 1)
+	ta.True(errors.Is(err, IllConditioned))
+}
+
+func TestFitQR_accuracy(t *testing.T) {
+
+	ta := assert.New(t)
+
+	rnd := rand.New(rand.NewSource(0))
+
+	for degree := 0; degree <= 5; degree++ {
+
+		// a polynomial with value in about [0, 10⁶] for x in [0, 1024)
+		want := make([]float64, degree+1)
+		for i := range want {
+			want[i] = (rnd.Float64() + 0.5) * 1e6 / math.Pow(1024, float64(i))
+		}
+
+		for _, start := range []int{0, 512, 960} {
+
+			xs := make([]float64, 0)
+			ys := make([]float64, 0)
+			for x := start; x < start+64; x++ {
+				xs = append(xs, float64(x))
+				ys = append(ys, eval(want, float64(x)))
+			}
+
+			f := NewFit(xs, ys, degree)
+			normal := f.Solve()
+			qr, cond, err := FitQR(xs, ys, degree)
+			ta.NoError(err)
+
+			normalErr, qrErr := float64(0), float64(0)
+			for i, x := range xs {
+				normalErr = math.Max(normalErr, math.Abs(eval(normal, x)-ys[i]))
+				qrErr = math.Max(qrErr, math.Abs(eval(qr, x)-ys[i]))
+			}
+
+			fmt.Printf("degree: %d, x: [%d, %d), cond(XᵀX): %.1e, cond(QR): %.1e, max error: normal equations: %.3e, QR: %.3e\n",
+				degree, start, start+64, f.Cond(), cond, normalErr, qrErr)
+
+			ta.True(qrErr < 1e-3, "degree: %d, start: %d, QR error: %v", degree, start, qrErr)
+			ta.True(qrErr <= normalErr+1e-6, "degree: %d, start: %d, QR error: %v, normal equations error: %v",
+				degree, start, qrErr, normalErr)
+			ta.True(cond <= f.Cond()+1e-9)
+		}
+	}
+}
+
+func TestFit_Cond(t *testing.T) {
+
+	ta := assert.New(t)
+
+	ta.Equal(float64(0), NewFit(nil, nil, 2).Cond())
+	ta.InDelta(1, NewFit([]float64{5}, []float64{1}, 2).Cond(), 1e-9)
+
+	// condition number grows with x
+	a := NewFit([]float64{0, 1, 2, 3}, []float64{1, 2, 3, 4}, 2)
+	b := NewFit([]float64{1000, 1001, 1002, 1003}, []float64{1, 2, 3, 4}, 2)
+	ta.True(a.Cond() < b.Cond())
+}
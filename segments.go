@@ -0,0 +1,151 @@
+package slimarray
+
+import (
+	"fmt"
+	"io"
+	"math/bits"
+
+	"github.com/openacid/slimarray/polyfit"
+)
+
+// SegmentInfo is a read-only description of a segment in a SlimArray.
+//
+// Since 0.1.15
+type SegmentInfo struct {
+	// Index of the segment.
+	Index int32
+
+	// Start and End are the index range [Start, End) of elts in this segment.
+	Start, End int32
+
+	// Spans in this segment.
+	Spans []SpanInfo
+}
+
+// SpanInfo is a read-only description of a span in a SlimArray.
+//
+// Since 0.1.15
+type SpanInfo struct {
+	// Start and End are the index range [Start, End) of elts in this span.
+	Start, End int32
+
+	// Polynomial is the coefficients [a, b, c] of y = a + bx + cx²,
+	// where x is the in-segment index of an elt.
+	Polynomial polyfit.Polynomial
+
+	// ResidualWidth is the number of bits to store a residual.
+	ResidualWidth int32
+
+	// Offset is the residual offset: the position of the residual of an elt is
+	// Offset + x * ResidualWidth.
+	Offset int64
+
+	// Bits is the number of bits this span costs, including polynomial, config
+	// and residuals.
+	Bits int64
+}
+
+// Segments returns description of every segment and span in it.
+// It is meant for diagnosing an array that does not compress well.
+//
+// Since 0.1.15
+func (sm *SlimArray) Segments() []SegmentInfo {
+
+	segs := make([]SegmentInfo, 0, len(sm.Bitmap))
+
+	spanIdx := 0
+	for segIdx, bm := range sm.Bitmap {
+
+		segStart := int32(segIdx) * segSize
+		segEnd := segStart + segSize
+		if segEnd > sm.N {
+			segEnd = sm.N
+		}
+
+		seg := SegmentInfo{
+			Index: int32(segIdx),
+			Start: segStart,
+			End:   segEnd,
+		}
+
+		s := segStart
+		for ; bm != 0; bm &= bm - 1 {
+
+			// the i-th "1" indicates the last 16 numbers of a span
+			e := segStart + (int32(bits.TrailingZeros64(bm))+1)*spanUnit
+			if e > segEnd {
+				e = segEnd
+			}
+
+			j := spanIdx * polyCoefCnt
+			poly := append(polyfit.Polynomial{}, sm.Polynomials[j:j+polyCoefCnt]...)
+
+			config := sm.Configs[spanIdx]
+			width := uint32(config & 0xff)
+
+			seg.Spans = append(seg.Spans, SpanInfo{
+				Start:         s,
+				End:           e,
+				Polynomial:    poly,
+				ResidualWidth: int32(width),
+				Offset:        config >> 8,
+				Bits:          int64(memCost(poly, width, e-s)),
+			})
+
+			s = e
+			spanIdx++
+		}
+
+		segs = append(segs, seg)
+	}
+
+	return segs
+}
+
+// String converts a span into human readable format, e.g.:
+//
+//    16-64(48): width: 4, offset: -64, bits: 448, poly: [1 2 3]
+//
+// Since 0.1.15
+func (sp SpanInfo) String() string {
+	return fmt.Sprintf("%d-%d(%d): width: %d, offset: %d, bits: %d, poly: %v",
+		sp.Start, sp.End, sp.End-sp.Start, sp.ResidualWidth, sp.Offset, sp.Bits, []float64(sp.Polynomial))
+}
+
+// DumpText writes description of every segment and span into w, e.g.:
+//
+//    seg 0: 0-1024(1024): spans: 2, bits/elt: 5
+//      0-16(16): width: 0, offset: 0, bits: 256, poly: [0 16 0]
+//      16-1024(1008): width: 4, offset: 0, bits: 4288, poly: [12.9 15.1 0.01]
+//
+// Since 0.1.15
+func (sm *SlimArray) DumpText(w io.Writer) error {
+
+	for _, seg := range sm.Segments() {
+
+		segBits := int64(0)
+		for _, sp := range seg.Spans {
+			segBits += sp.Bits
+		}
+
+		n := int64(seg.End - seg.Start)
+		if n == 0 {
+			n = 1
+		}
+
+		_, err := fmt.Fprintf(w, "seg %d: %d-%d(%d): spans: %d, bits/elt: %d\n",
+			seg.Index, seg.Start, seg.End, seg.End-seg.Start, len(seg.Spans), segBits/n)
+		if err != nil {
+			return err
+		}
+
+		for _, sp := range seg.Spans {
+			_, err := fmt.Fprintf(w, "  %s\n", sp)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,83 @@
+package slimarray
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/openacid/low/bitmap"
+	"github.com/openacid/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSlimArray_Segments(t *testing.T) {
+
+	ta := require.New(t)
+
+	cases := [][]uint32{
+		{},
+		{1},
+		testNums,
+		testutil.RandU32Slice(0, 1024*3+5, 100),
+	}
+
+	for _, nums := range cases {
+
+		a := NewU32(nums)
+		segs := a.Segments()
+		ta.Equal(len(a.Bitmap), len(segs))
+
+		i := int32(0)
+		for k, seg := range segs {
+			ta.Equal(int32(k), seg.Index)
+			ta.Equal(i, seg.Start)
+
+			for _, sp := range seg.Spans {
+				ta.Equal(i, sp.Start)
+				ta.True(sp.End > sp.Start)
+
+				for ; i < sp.End; i++ {
+					// re-calculate elt with span info
+					v := sp.Polynomial.EvalInt(int64(i - seg.Start))
+
+					resBitIdx := sp.Offset + int64(i-seg.Start)*int64(sp.ResidualWidth)
+					d := a.Residuals[resBitIdx>>6] >> uint(resBitIdx&63)
+					d &= bitmap.Mask[sp.ResidualWidth]
+
+					ta.Equal(nums[i], uint32(v+int64(d)))
+				}
+			}
+			ta.Equal(i, seg.End)
+		}
+		ta.Equal(int32(len(nums)), i)
+	}
+}
+
+func TestSlimArray_DumpText(t *testing.T) {
+
+	ta := require.New(t)
+
+	a := NewU32(testNums)
+
+	var buf bytes.Buffer
+	err := a.DumpText(&buf)
+	ta.NoError(err)
+
+	fmt.Println(buf.String())
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	ta.Equal(1+5, len(lines))
+	ta.True(strings.HasPrefix(lines[0], "seg 0: 0-354(354): spans: 5, bits/elt: "))
+	ta.True(strings.HasPrefix(lines[1], "  0-16(16): width: "))
+
+	err = a.DumpText(errWriter{})
+	ta.Error(err)
+}
+
+type errWriter struct{}
+
+func (w errWriter) Write(p []byte) (int, error) {
+	return 0, errors.New("write error")
+}
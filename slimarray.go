@@ -150,7 +150,6 @@ import (
 	"math/bits"
 
 	"github.com/openacid/low/bitmap"
-	"github.com/openacid/low/size"
 	"github.com/openacid/slimarray/polyfit"
 )
 
@@ -176,45 +175,136 @@ const (
 	polyCoefCnt = polyDegree + 1
 )
 
-// evalPoly2 evaluates a polynomial with degree=2.
-//
-// Since 0.1.1
-func evalPoly2(poly []float64, x int32) float64 {
-	v := float64(x)
-	return poly[0] + v*poly[1] + v*v*poly[2]
-}
-
 // NewU32 creates a "SlimArray" array from a slice of uint32.
 //
 // A NewU32() costs about 110 ns/elt.
 //
 // Since 0.1.1
 func NewU32(nums []uint32) *SlimArray {
+	return NewU32Opt(nums, nil)
+}
+
+// NewU32Opt creates a "SlimArray" array from a slice of uint32, with options
+// to control how it is built.
+// A nil opt is the same as NewU32().
+//
+// Since 0.1.15
+func NewU32Opt(nums []uint32, opt *Opt) *SlimArray {
+	return newU32(nums, 0, opt)
+}
+
+func newU32(nums []uint32, maxErr uint32, opt *Opt) *SlimArray {
+
+	opt = opt.withDefault()
 
 	pa := &SlimArray{
-		N: int32(len(nums)),
+		N:      int32(len(nums)),
+		MaxErr: maxErr,
 	}
 
 	for ; len(nums) > segSize; nums = nums[segSize:] {
-		pa.addSeg(nums[:segSize])
+		pa.addSeg(nums[:segSize], opt)
 	}
 	if len(nums) > 0 {
-		pa.addSeg(nums)
+		pa.addSeg(nums, opt)
 	}
 
-	// shrink capacity to len.
-	pa.Rank = append(pa.Rank[:0:0], pa.Rank...)
-	pa.Bitmap = append(pa.Bitmap[:0:0], pa.Bitmap...)
-	pa.Polynomials = append(pa.Polynomials[:0:0], pa.Polynomials...)
-	pa.Configs = append(pa.Configs[:0:0], pa.Configs...)
+	pa.shrink()
 
-	// Add another empty word to avoid panic for residual of width = 0.
-	pa.Residuals = append(pa.Residuals, 0)
-	pa.Residuals = append(pa.Residuals[:0:0], pa.Residuals...)
+	return pa
+}
+
+// NewU64 creates a "SlimArray" array from a slice of uint64.
+// Elements are retrieved with GetU64() or Get2U64().
+//
+// An elt must be smaller than 2^63.
+//
+// Since 0.1.15
+func NewU64(nums []uint64) *SlimArray {
+	return NewU64Opt(nums, nil)
+}
+
+// NewU64Opt creates a "SlimArray" array from a slice of uint64, with options
+// to control how it is built.
+// A nil opt is the same as NewU64().
+//
+// Since 0.1.15
+func NewU64Opt(nums []uint64, opt *Opt) *SlimArray {
+
+	opt = opt.withDefault()
+
+	pa := &SlimArray{
+		N:        int32(len(nums)),
+		EltWidth: 64,
+	}
+
+	for ; len(nums) > segSize; nums = nums[segSize:] {
+		pa.addSeg64(nums[:segSize], opt)
+	}
+	if len(nums) > 0 {
+		pa.addSeg64(nums, opt)
+	}
+
+	pa.shrink()
 
 	return pa
 }
 
+// u64Builder builds a SlimArray of uint64 incrementally.
+// A segment is compressed as soon as it is full.
+//
+// Since 0.1.15
+type u64Builder struct {
+	sm  *SlimArray
+	seg []uint64
+	opt *Opt
+}
+
+func newU64Builder() *u64Builder {
+	return &u64Builder{
+		sm: &SlimArray{
+			EltWidth: 64,
+		},
+		seg: make([]uint64, 0, segSize),
+		opt: (*Opt)(nil).withDefault(),
+	}
+}
+
+func (b *u64Builder) add(v uint64) {
+	b.seg = append(b.seg, v)
+	b.sm.N++
+
+	if len(b.seg) == segSize {
+		b.sm.addSeg64(b.seg, b.opt)
+		b.seg = b.seg[:0]
+	}
+}
+
+func (b *u64Builder) finish() *SlimArray {
+	if len(b.seg) > 0 {
+		b.sm.addSeg64(b.seg, b.opt)
+		b.seg = b.seg[:0]
+	}
+
+	sm := b.sm
+	sm.shrink()
+	b.sm = nil
+
+	return sm
+}
+
+// shrink capacity to len.
+func (sm *SlimArray) shrink() {
+	sm.Rank = append(sm.Rank[:0:0], sm.Rank...)
+	sm.Bitmap = append(sm.Bitmap[:0:0], sm.Bitmap...)
+	sm.Polynomials = append(sm.Polynomials[:0:0], sm.Polynomials...)
+	sm.Configs = append(sm.Configs[:0:0], sm.Configs...)
+
+	// Add another empty word to avoid panic for residual of width = 0.
+	sm.Residuals = append(sm.Residuals, 0)
+	sm.Residuals = append(sm.Residuals[:0:0], sm.Residuals...)
+}
+
 // Get returns the uncompressed uint32 value.
 // A Get() costs about 7 ns
 //
@@ -227,7 +317,6 @@ func (sm *SlimArray) Get(i int32) uint32 {
 	rank := sm.Rank[bitmapI]
 
 	i = i & segSizeMask
-	x := float64(i)
 
 	// i>>4 is in-segment span index
 	bm := spansBitmap & bitmap.Mask[i>>4]
@@ -236,8 +325,8 @@ func (sm *SlimArray) Get(i int32) uint32 {
 	// eval y = a + bx + cx²
 
 	j := spanIdx * polyCoefCnt
-	p := sm.Polynomials
-	v := int64(p[j] + x*p[j+1] + x*x*p[j+2])
+	poly := polyfit.Polynomial(sm.Polynomials[j : j+polyCoefCnt])
+	v := poly.EvalInt(int64(i))
 
 	config := sm.Configs[spanIdx]
 	residualWidth := config & 0xff
@@ -249,8 +338,13 @@ func (sm *SlimArray) Get(i int32) uint32 {
 	// extract residual from packed []uint64
 	d := sm.Residuals[resBitIdx>>6]
 	d = d >> uint(resBitIdx&63)
+	d &= bitmap.Mask[residualWidth]
+
+	if sm.MaxErr > 0 {
+		return sm.dequantize(v, d)
+	}
 
-	return uint32(v + int64(d&bitmap.Mask[residualWidth]))
+	return uint32(v + int64(d))
 }
 
 // Get2 returns two uncompressed uint32 value at i and i + 1.
@@ -274,7 +368,6 @@ func (sm *SlimArray) Get2(i int32) (uint32, uint32) {
 	rank := sm.Rank[bitmapI]
 
 	i = i & segSizeMask
-	x := float64(i)
 
 	// i>>4 is in-segment span index
 	bm := spansBitmap & bitmap.Mask[i>>4]
@@ -283,8 +376,8 @@ func (sm *SlimArray) Get2(i int32) (uint32, uint32) {
 	// eval y = a + bx + cx²
 
 	j := spanIdx * polyCoefCnt
-	p := sm.Polynomials
-	v := int64(p[j] + x*p[j+1] + x*x*p[j+2])
+	poly := polyfit.Polynomial(sm.Polynomials[j : j+polyCoefCnt])
+	v1 := poly.EvalInt(int64(i))
 
 	config := sm.Configs[spanIdx]
 	residualWidth := config & 0xff
@@ -298,13 +391,12 @@ func (sm *SlimArray) Get2(i int32) (uint32, uint32) {
 	d = d >> uint(resBitIdx&63)
 
 	mask := bitmap.Mask[residualWidth]
-	rst1 := uint32(v + int64(d&mask))
+	q1 := d & mask
 
 	// the second: i+1 th value
 	//
 	// The index of a segment
-	x += 1
-	v = int64(p[j] + x*p[j+1] + x*x*p[j+2])
+	v2 := poly.EvalInt(int64(i) + 1)
 
 	// where the residual is
 	resBitIdx += residualWidth
@@ -313,7 +405,99 @@ func (sm *SlimArray) Get2(i int32) (uint32, uint32) {
 	d = sm.Residuals[resBitIdx>>6]
 	d = d >> uint(resBitIdx&63)
 
-	rst2 := uint32(v + int64(d&mask))
+	q2 := d & mask
+
+	if sm.MaxErr > 0 {
+		return sm.dequantize(v1, q1), sm.dequantize(v2, q2)
+	}
+
+	return uint32(v1 + int64(q1)), uint32(v2 + int64(q2))
+}
+
+// GetU64 returns the uncompressed uint64 value.
+// It should only be used on a SlimArray created by NewU64().
+//
+// Since 0.1.15
+func (sm *SlimArray) GetU64(i int32) uint64 {
+
+	// The index of a segment
+	bitmapI := i >> segSizeShift
+	spansBitmap := sm.Bitmap[bitmapI]
+	rank := sm.Rank[bitmapI]
+
+	i = i & segSizeMask
+
+	// i>>4 is in-segment span index
+	bm := spansBitmap & bitmap.Mask[i>>4]
+	spanIdx := int(rank) + bits.OnesCount64(bm)
+
+	// eval y = a + bx + cx²
+
+	j := spanIdx * polyCoefCnt
+	poly := polyfit.Polynomial(sm.Polynomials[j : j+polyCoefCnt])
+	v := poly.EvalInt(int64(i))
+
+	config := sm.Configs[spanIdx]
+	residualWidth := config & 0xff
+	offset := config >> 8
+
+	// where the residual is
+	resBitIdx := offset + int64(i)*residualWidth
+
+	// extract residual from packed []uint64
+	d := sm.Residuals[resBitIdx>>6]
+	d = d >> uint(resBitIdx&63)
+
+	return uint64(v) + d&bitmap.Mask[residualWidth]
+}
+
+// Get2U64 returns two uncompressed uint64 value at i and i + 1.
+// It should only be used on a SlimArray created by NewU64().
+//
+// Since 0.1.15
+func (sm *SlimArray) Get2U64(i int32) (uint64, uint64) {
+
+	if i&0xf == 0xf {
+		return sm.GetU64(i), sm.GetU64(i + 1)
+	}
+
+	// else: i and i+1 must be in the same span.
+
+	bitmapI := i >> segSizeShift
+	spansBitmap := sm.Bitmap[bitmapI]
+	rank := sm.Rank[bitmapI]
+
+	i = i & segSizeMask
+
+	bm := spansBitmap & bitmap.Mask[i>>4]
+	spanIdx := int(rank) + bits.OnesCount64(bm)
+
+	j := spanIdx * polyCoefCnt
+	poly := polyfit.Polynomial(sm.Polynomials[j : j+polyCoefCnt])
+	v := poly.EvalInt(int64(i))
+
+	config := sm.Configs[spanIdx]
+	residualWidth := config & 0xff
+	offset := config >> 8
+
+	resBitIdx := offset + int64(i)*residualWidth
+
+	d := sm.Residuals[resBitIdx>>6]
+	d = d >> uint(resBitIdx&63)
+
+	mask := bitmap.Mask[residualWidth]
+	rst1 := uint64(v) + d&mask
+
+	// the second: i+1 th value
+
+	v = poly.EvalInt(int64(i) + 1)
+
+	resBitIdx += residualWidth
+
+	d = sm.Residuals[resBitIdx>>6]
+	d = d >> uint(resBitIdx&63)
+
+	rst2 := uint64(v) + d&mask
 
 	return rst1, rst2
 }
@@ -331,6 +515,9 @@ func (sm *SlimArray) Slice(start int32, end int32, rst []uint32) {
 	if end > sm.N {
 		end = sm.N
 	}
+	if start >= end {
+		return
+	}
 
 	ctx := &queryContext{
 		sm: sm,
@@ -345,24 +532,31 @@ func (sm *SlimArray) Slice(start int32, end int32, rst []uint32) {
 
 		// eval y = a + bx + cx²
 
-		x := float64(ctx.inSegIdx)
-		v := int64(ctx.b0 + x*ctx.b1 + x*x*ctx.b2)
+		v := ctx.poly.EvalInt(int64(ctx.inSegIdx))
 
 		// extract residual from packed []uint64
 		d := sm.Residuals[resBitIdx>>6]
 		d = d >> uint(resBitIdx&63)
 
-		rst[start-i0] = uint32(v + int64(d&ctx.resMask))
+		d &= ctx.resMask
+
+		if sm.MaxErr > 0 {
+			rst[start-i0] = sm.dequantize(v, d)
+		} else {
+			rst[start-i0] = uint32(v + int64(d))
+		}
 
 		ctx.inSegIdx++
 		resBitIdx += ctx.residualWidth
 
-		// entered next span-unit
-		if ctx.inSegIdx&0x0f == 0 {
+		// entered next span-unit.
+		// Do not init the next span if this is the last elt: it may be out of
+		// range.
+		if ctx.inSegIdx&0x0f == 0 && start+1 < end {
 
-			// entered next seg
+			// entered next seg, the next elt is start+1
 			if ctx.inSegIdx == segSize {
-				ctx.initSeg(start)
+				ctx.initSeg(start + 1)
 			}
 
 			ctx.initSpan()
@@ -383,7 +577,7 @@ type queryContext struct {
 	spanUnitIdx   int32
 	bitmap        uint64
 	spanIdx       int
-	b0, b1, b2    float64
+	poly          polyfit.Polynomial
 	spanConfig    int64
 	residualWidth int64
 	resMask       uint64
@@ -402,9 +596,7 @@ func (q *queryContext) initSpan() {
 	q.bitmap = q.spansBitmap & bitmap.Mask[q.spanUnitIdx]
 	q.spanIdx = q.rank + bits.OnesCount64(q.bitmap)
 	polyOffset := q.spanIdx * polyCoefCnt
-	q.b0 = q.sm.Polynomials[polyOffset]
-	q.b1 = q.sm.Polynomials[polyOffset+1]
-	q.b2 = q.sm.Polynomials[polyOffset+2]
+	q.poly = q.sm.Polynomials[polyOffset : polyOffset+polyCoefCnt]
 	q.spanConfig = q.sm.Configs[q.spanIdx]
 	q.residualWidth = q.spanConfig & 0xff
 	q.resMask = bitmap.Mask[q.residualWidth]
@@ -430,45 +622,39 @@ func (sm *SlimArray) Len() int {
 //    bits/elt  :9           // average memory cost per elt
 //    n         :10          // total elt count
 //
+// A value overflows if it exceeds max value of int32.
+// Use Stats() for a complete memory breakdown.
+//
 // Since 0.1.1
 func (sm *SlimArray) Stat() map[string]int32 {
-	segCnt := len(sm.Bitmap)
-	totalmem := size.Of(sm)
-
-	spanCnt := len(sm.Polynomials) / polyCoefCnt
-	memWords := len(sm.Residuals) * 8
-	widthAvg := 0
-	for i := 0; i < spanCnt; i++ {
-		w := sm.Configs[i] & 0xff
-		widthAvg += int(w)
-	}
-
-	n := sm.Len()
-	if n == 0 {
-		n = 1
+	st := map[string]int32{}
+	for k, v := range sm.Stats().statMap() {
+		st[k] = int32(v)
 	}
+	return st
+}
 
-	if spanCnt == 0 {
-		spanCnt = 1
-	}
+func (sm *SlimArray) addSeg(nums []uint32, opt *Opt) {
+	var bm uint64
+	var polynomials []float64
+	var configs []int64
+	var words []uint64
 
-	st := map[string]int32{
-		"seg_cnt":   int32(segCnt),
-		"elt_width": int32(widthAvg / spanCnt),
-		"mem_total": int32(totalmem),
-		"mem_elts":  int32(memWords),
-		"bits/elt":  int32(totalmem * 8 / n),
-		"spans/seg": int32((spanCnt * 1000) / (segCnt*1000 + 1)),
-		"span_cnt":  int32(spanCnt),
-		"n":         sm.N,
+	start := int64(len(sm.Residuals) * 64)
+	if sm.MaxErr > 0 {
+		bm, polynomials, configs, words = newSegLossy(nums, start, opt, sm.MaxErr)
+	} else {
+		bm, polynomials, configs, words = newSeg(nums, start, opt)
 	}
-
-	return st
+	sm.appendSeg(bm, polynomials, configs, words)
 }
 
-func (sm *SlimArray) addSeg(nums []uint32) {
+func (sm *SlimArray) addSeg64(nums []uint64, opt *Opt) {
+	bm, polynomials, configs, words := newSeg64(nums, int64(len(sm.Residuals)*64), opt)
+	sm.appendSeg(bm, polynomials, configs, words)
+}
 
-	bm, polynomials, configs, words := newSeg(nums, int64(len(sm.Residuals)*64))
+func (sm *SlimArray) appendSeg(bm uint64, polynomials []float64, configs []int64, words []uint64) {
 
 	var r uint64
 	l := len(sm.Rank)
@@ -485,7 +671,7 @@ func (sm *SlimArray) addSeg(nums []uint32) {
 	sm.Residuals = append(sm.Residuals, words...)
 }
 
-func newSeg(nums []uint32, start int64) (uint64, []float64, []int64, []uint64) {
+func newSeg(nums []uint32, start int64, opt *Opt) (uint64, []float64, []int64, []uint64) {
 
 	n := int32(len(nums))
 	ys := make([]float64, n)
@@ -494,10 +680,38 @@ func newSeg(nums []uint32, start int64) (uint64, []float64, []int64, []uint64) {
 		ys[i] = float64(v)
 	}
 
-	// create polynomial fit sessions for every 16 numbers
-	fts := initFittings(n, ys, spanUnit)
+	spans := fitSpans(ys, opt)
+
+	return packSpans(spans, n, start, func(j int32, v float64) uint64 {
+		// It may overflow but the result is correct because (a+b) % p =
+		// (a%p + b%p) % p
+		return uint64(uint32(int64(nums[j]) - int64(v)))
+	})
+}
 
-	spans := findMinFittingsNew(ys, fts)
+func newSeg64(nums []uint64, start int64, opt *Opt) (uint64, []float64, []int64, []uint64) {
+
+	n := int32(len(nums))
+	ys := make([]float64, n)
+
+	for i, v := range nums {
+		ys[i] = float64(v)
+	}
+
+	spans := fitSpans(ys, opt)
+	for _, sp := range spans {
+		sp.fitResiduals64(nums)
+	}
+
+	return packSpans(spans, n, start, func(j int32, v float64) uint64 {
+		return nums[j] - uint64(int64(v))
+	})
+}
+
+// packSpans builds the compacted segment from spans.
+// residual(j, v) returns the residual of the j-th elt, where v is the value
+// evaluated with the polynomial of the span.
+func packSpans(spans []*span, n int32, start int64, residual func(j int32, v float64) uint64) (uint64, []float64, []int64, []uint64) {
 
 	polynomials := make([]float64, 0, 1024/16)
 	configs := make([]int64, 0, 1024/16)
@@ -530,14 +744,11 @@ func newSeg(nums []uint32, start int64) (uint64, []float64, []int64, []uint64) {
 
 		for j := sp.s; j < sp.e; j++ {
 
-			v := evalPoly2(sp.poly, j)
-
-			// It may overflow but the result is correct because (a+b) % p =
-			// (a%p + b%p) % p
-			d := uint32(int64(nums[j]) - int64(v))
+			v := polyfit.Polynomial(sp.poly).Eval(float64(j))
+			d := residual(j, v)
 
 			wordI := resI >> 6
-			words[wordI] |= uint64(d) << uint(resI&63)
+			words[wordI] |= d << uint(resI&63)
 
 			resI += int64(width)
 		}
@@ -548,6 +759,35 @@ func newSeg(nums []uint32, start int64) (uint64, []float64, []int64, []uint64) {
 	return segPolyBitmap, polynomials, configs, words[:nWords]
 }
 
+// fitSpans splits a segment into spans and fits every span with a polynomial.
+//
+// Since 0.1.15
+func fitSpans(ys []float64, opt *Opt) []*span {
+
+	n := int32(len(ys))
+
+	var spans []*span
+
+	switch opt.Mode {
+	case FastBuild:
+		fts := initFittings(n, ys, fastSpanSize)
+		spans = newSpans(ys, fts)
+	case MaxCompression:
+		fts := initFittings(n, ys, spanUnit)
+		spans = findOptimalFittings(ys, fts)
+	default:
+		spans = findMinFittings(ys)
+	}
+
+	if opt.Minimax {
+		for _, sp := range spans {
+			sp.fitMinimax(ys)
+		}
+	}
+
+	return spans
+}
+
 func initFittings(n int32, ys []float64, spanSize int32) []*polyfit.Fit {
 
 	fts := make([]*polyfit.Fit, 0, n/spanSize+1)
@@ -576,134 +816,195 @@ type span struct {
 	s, e int32
 }
 
-func (sp *span) Copy() *span {
-	b := &span{
-		ft:       sp.ft.Copy(),
-		origPoly: make([]float64, 0, len(sp.origPoly)),
-		poly:     make([]float64, 0, len(sp.poly)),
-		mem:      sp.mem,
-		s:        sp.s,
-		e:        sp.e,
-	}
-
-	b.origPoly = append(b.origPoly, sp.origPoly...)
-	b.poly = append(b.poly, sp.poly...)
-	return b
-}
-
 func (sp *span) String() string {
 	return fmt.Sprintf("%d-%d(%d): width: %d, mem: %d, poly: %v",
 		sp.s, sp.e, sp.e-sp.s, sp.residualWidth, sp.mem, sp.poly)
 }
 
-// findMinFittingsNew by merge adjacent 16-numbers span.
+// findMinFittings by merge adjacent 16-numbers span.
 // If two spans has a common trend they should be described with one polynomial.
-func findMinFittingsNew(ys []float64, fts []*polyfit.Fit) []*span {
-
-	spans := make([]*span, len(fts))
-	merged := make([]*span, len(fts)-1)
+func findMinFittings(ys []float64) []*span {
 
-	var s, e int32
-	s = 0
-	for i, ft := range fts {
+	xs := make([]float64, len(ys))
+	for i := range xs {
+		xs[i] = float64(i)
+	}
 
-		e = s + int32(ft.N)
+	pieces := polyfit.PiecewiseUnit(xs, ys, polyDegree, int(spanUnit), spanCost)
 
-		sp := newSpan(ys, ft, s, e)
+	spans := make([]*span, len(pieces))
+	for i, p := range pieces {
+		sp := &span{
+			origPoly: p.Poly,
+			s:        int32(p.Start),
+			e:        int32(p.End),
+		}
+		sp.updatePolyAndStat(ys)
 		spans[i] = sp
-		s = e
 	}
 
-	for i, sp := range spans[:len(spans)-1] {
-		sp2 := spans[i+1]
+	return spans
+}
+
+// spanCost is the polyfit.CostFunc of memory cost of a span.
+func spanCost(p *polyfit.Piece) float64 {
+	margin := int64(math.Ceil(p.Residuals.Max - p.Residuals.Min))
+	residualWidth := marginWidth(margin)
+	if residualWidth > 32 {
+		residualWidth = 32
+	}
+	return float64(memCost(p.Poly, residualWidth, int32(p.End-p.Start)))
+}
+
+// findOptimalFittings finds the span layout with minimal memory cost with
+// dynamic programming.
+//
+// The cost of a span layout is the sum of cost of every span.
+// With minCost[j] the minimal cost of the first j span units, we have:
+//
+//    minCost[j] = min(minCost[i] + cost(span of unit i to j)), 0 <= i < j
+//
+// There are at most 64 span units in a segment, thus it evaluates at most
+// 64*65/2 candidate spans. The fitting of span i to j is merged from the
+// fitting of span i to j-1.
+//
+// Since 0.1.15
+func findOptimalFittings(ys []float64, fts []*polyfit.Fit) []*span {
 
-		merged[i] = sp.Copy()
-		mergeTwoSpan(ys, merged[i], sp2)
+	nUnits := len(fts)
+
+	// start index in ys of every span unit
+	starts := make([]int32, nUnits+1)
+	for i, ft := range fts {
+		starts[i+1] = starts[i] + int32(ft.N)
 	}
 
-	for len(merged) > 0 {
+	minCost := make([]int, nUnits+1)
+	prev := make([]int, nUnits+1)
+	for j := 1; j <= nUnits; j++ {
+		minCost[j] = math.MaxInt32
+	}
 
-		// find minimal merge and merge
+	sp := &span{}
+	for i := 0; i < nUnits; i++ {
 
-		maxReduced := -1
-		maxI := 0
+		ft := fts[i].Copy()
 
-		for i := 1; i < len(merged); i++ {
-			a := spans[i]
-			b := spans[i+1]
-			mr := merged[i]
-			reduced := a.mem + b.mem - mr.mem
-			if maxReduced < reduced {
-				maxI = i
-				maxReduced = reduced
+		for j := i + 1; j <= nUnits; j++ {
+			if j > i+1 {
+				ft.Merge(fts[j-1])
 			}
-		}
 
-		// maxI -> b
-		//
-		// span:     a  b  c  d
-		// merged:    ab bc cd
-		//
-		// becomes:
-		//
-		// span:     a   bc   d
-		// merged:    abc  bcd
-		//
-		// a => a
-		// b => nil
-		// c => nil
-		// d => d
-		// ab + c => abc
-		// bc => bc
-		// b + cd => bcd
-
-		if maxReduced > 0 {
-
-			// a  b  c  d
-			// abc bc cd
-			if maxI > 0 {
-				mergeTwoSpan(ys, merged[maxI-1], spans[maxI+1])
+			sp.ft = ft
+			sp.s = starts[i]
+			sp.e = starts[j]
+			sp.solve()
+
+			// A candidate is useless if it costs more than minCost[j]:
+			//    minCost[i] + 64*(polyCoefCnt+1) + width*n >= minCost[j]
+			n := int(sp.e - sp.s)
+			maxWidth := (minCost[j] - minCost[i] - memCost(sp.origPoly, 0, 0)) / n
+			mem, ok := sp.boundedMem(ys, maxWidth)
+			if !ok {
+				continue
 			}
 
-			// a  bcd  c  d
-			// abc bc bcd
-			if maxI < len(merged)-1 {
-				mergeTwoSpan(ys, spans[maxI], merged[maxI+1])
-				merged[maxI+1] = spans[maxI]
+			c := minCost[i] + mem
+			if c < minCost[j] {
+				minCost[j] = c
+				prev[j] = i
 			}
+		}
+	}
 
-			// a  bc  d
-			// abc bc bcd
-			spans[maxI] = merged[maxI]
-			spans = append(spans[:maxI+1], spans[maxI+2:]...)
+	// rebuild the optimal spans from the last one.
+	var bounds []int
+	for j := nUnits; j > 0; j = prev[j] {
+		bounds = append(bounds, j)
+	}
 
-			// a  bc  d
-			// abc  bcd
-			merged = append(merged[:maxI], merged[maxI+1:]...)
+	spans := make([]*span, 0, len(bounds))
+	i := 0
+	for k := len(bounds) - 1; k >= 0; k-- {
+		j := bounds[k]
 
-		} else {
-			// Even the minimal merge does not reduce memory cost.
-			break
+		ft := fts[i].Copy()
+		for u := i + 1; u < j; u++ {
+			ft.Merge(fts[u])
 		}
+		spans = append(spans, newSpan(ys, ft, starts[i], starts[j]))
+		i = j
 	}
 
 	return spans
 }
 
-func mergeTwoSpan(ys []float64, a, b *span) {
-	a.ft.Merge(b.ft)
-	a.e = b.e
+// boundedMem returns the memory cost of a span with the solved polynomial
+// sp.origPoly, the same as sp.mem calculated by updatePolyAndStat.
+// It returns false as soon as it finds residual width exceeds maxWidth.
+//
+// Since 0.1.15
+func (sp *span) boundedMem(ys []float64, maxWidth int) (int, bool) {
 
-	// policy: re-fit curve
-	a.solve()
-	a.updatePolyAndStat(ys)
+	if maxWidth < 0 {
+		return 0, false
+	}
 
-	// // policy: mean curve
-	// // twice faster than re-fit, also results in twice memory cost.
-	// for i, c := range b.origPoly {
-	//     a.origPoly[i] = (a.origPoly[i] + c) / 2
-	// }
-	// a.updatePolyAndStat(ys)
+	// Max margin a residual width of maxWidth can hold, i.e., the max margin
+	// for marginWidth(margin) <= maxWidth
+	maxMargin := math.Inf(1)
+	if maxWidth < 32 {
+		// align width to 2^k
+		w := uint(bits.Len(uint(maxWidth))) - 1
+		maxMargin = float64(uint64(1)<<(uint(1)<<w)) - 1
+		if maxWidth == 0 {
+			maxMargin = 0
+		}
+	}
+
+	max, min := float64(0), float64(0)
+
+	for i := sp.s; i < sp.e; i++ {
+
+		v := polyfit.Polynomial(sp.origPoly).Eval(float64(i))
+		diff := ys[i] - v
+		if diff > max {
+			max = diff
+		}
+		if diff < min {
+			min = diff
+		}
+
+		if math.Ceil(max-min) > maxMargin {
+			return 0, false
+		}
+	}
+
+	residualWidth := marginWidth(int64(math.Ceil(max - min)))
+	if residualWidth > 32 {
+		residualWidth = 32
+	}
+
+	return memCost(sp.origPoly, residualWidth, sp.e-sp.s), true
+}
+
+// newSpans creates a span for every fitting.
+func newSpans(ys []float64, fts []*polyfit.Fit) []*span {
+
+	spans := make([]*span, len(fts))
+
+	var s, e int32
+	s = 0
+	for i, ft := range fts {
+
+		e = s + int32(ft.N)
+
+		sp := newSpan(ys, ft, s, e)
+		spans[i] = sp
+		s = e
+	}
+
+	return spans
 }
 
 func newSpan(ys []float64, ft *polyfit.Fit, s, e int32) *span {
@@ -723,6 +1024,36 @@ func (sp *span) solve() {
 	sp.origPoly = sp.ft.Solve()
 }
 
+// fitMinimax replaces the least squares polynomial of a span with the minimax
+// polynomial, if the latter requires narrower residuals.
+//
+// Since 0.1.15
+func (sp *span) fitMinimax(ys []float64) {
+
+	if sp.residualWidth == 0 {
+		return
+	}
+
+	xs := make([]float64, sp.e-sp.s)
+	for i := range xs {
+		xs[i] = float64(sp.s + int32(i))
+	}
+
+	poly, _ := polyfit.Minimax(xs, ys[sp.s:sp.e], polyDegree)
+
+	b := &span{
+		ft:       sp.ft,
+		origPoly: poly,
+		s:        sp.s,
+		e:        sp.e,
+	}
+	b.updatePolyAndStat(ys)
+
+	if b.residualWidth < sp.residualWidth {
+		*sp = *b
+	}
+}
+
 func (sp *span) updatePolyAndStat(ys []float64) {
 	s, e := sp.s, sp.e
 	max, min := sp.maxMinResiduals(ys[s:e])
@@ -737,8 +1068,52 @@ func (sp *span) updatePolyAndStat(ys []float64) {
 	}
 	sp.residualWidth = residualWidth
 
-	sp.mem = memCost(sp.poly, residualWidth, int32(sp.ft.N))
+	sp.mem = memCost(sp.poly, residualWidth, sp.e-sp.s)
+
+}
+
+// fitResiduals64 makes sure every residual of a span of uint64 is non-negative
+// and fits in residualWidth bits.
+//
+// A float64 has only 53 significant bits, the residuals calculated with float64
+// are inaccurate for large numbers. Thus residuals are re-calculated with
+// int64.
+//
+// Since 0.1.15
+func (sp *span) fitResiduals64(nums []uint64) {
+
+	for k := 0; k < 8; k++ {
+
+		max, min := int64(math.MinInt64), int64(math.MaxInt64)
+		for j := sp.s; j < sp.e; j++ {
+			v := int64(polyfit.Polynomial(sp.poly).Eval(float64(j)))
+			d := int64(nums[j] - uint64(v))
+			if d > max {
+				max = d
+			}
+			if d < min {
+				min = d
+			}
+		}
+
+		if min >= 0 {
+			sp.residualWidth = marginWidth(max)
+			sp.mem = memCost(sp.poly, sp.residualWidth, sp.e-sp.s)
+			return
+		}
+
+		// move the curve down so that residuals are all non-negative.
+		p0 := sp.poly[0] + float64(min)
+		if p0 == sp.poly[0] {
+			p0 = math.Nextafter(p0, math.Inf(-1))
+		}
+		sp.poly[0] = p0
+	}
 
+	// Residuals are stored in 64 bits: the result is always correct since
+	// (a+b) % 2^64 = (a%2^64 + b%2^64) % 2^64
+	sp.residualWidth = 64
+	sp.mem = memCost(sp.poly, sp.residualWidth, sp.e-sp.s)
 }
 
 // marginWidth calculate the minimal number of bits to store `margin`.
@@ -773,7 +1148,7 @@ func (sp *span) maxMinResiduals(ys []float64) (float64, float64) {
 
 	for i := sp.s; i < sp.e; i++ {
 
-		v := evalPoly2(sp.origPoly, i)
+		v := polyfit.Polynomial(sp.origPoly).Eval(float64(i))
 		diff := ys[i-sp.s] - v
 		if diff > max {
 			max = diff
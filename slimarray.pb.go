@@ -48,8 +48,20 @@ type SlimArray struct {
 	unknownFields protoimpl.UnknownFields
 
 	// N is the count of elts
-	N    int32    `protobuf:"varint,10,opt,name=N,proto3" json:"N,omitempty"`
-	Rank []uint64 `protobuf:"varint,19,rep,packed,name=Rank,proto3" json:"Rank,omitempty"`
+	N int32 `protobuf:"varint,10,opt,name=N,proto3" json:"N,omitempty"`
+	// EltWidth is the number of bits of an elt.
+	// It is 0 for an array of uint32 created by NewU32,
+	// or 64 for an array of uint64 created by NewU64.
+	//
+	// Since 0.1.15
+	EltWidth int32 `protobuf:"varint,11,opt,name=EltWidth,proto3" json:"EltWidth,omitempty"`
+	// MaxErr is the max error of an elt in a lossy SlimArray created by
+	// NewU32Lossy: |Get(i) - nums[i]| <= MaxErr.
+	// It is 0 for a lossless SlimArray.
+	//
+	// Since 0.1.15
+	MaxErr uint32   `protobuf:"varint,12,opt,name=MaxErr,proto3" json:"MaxErr,omitempty"`
+	Rank   []uint64 `protobuf:"varint,19,rep,packed,name=Rank,proto3" json:"Rank,omitempty"`
 	// Every 1024 elts segment has a 64-bit bitmap to describe the spans in it,
 	// and another 64-bit rank: the count of `1` in preceding bitmaps.
 	Bitmap []uint64 `protobuf:"varint,20,rep,packed,name=Bitmap,proto3" json:"Bitmap,omitempty"`
@@ -102,6 +114,20 @@ func (x *SlimArray) GetN() int32 {
 	return 0
 }
 
+func (x *SlimArray) GetEltWidth() int32 {
+	if x != nil {
+		return x.EltWidth
+	}
+	return 0
+}
+
+func (x *SlimArray) GetMaxErr() uint32 {
+	if x != nil {
+		return x.MaxErr
+	}
+	return 0
+}
+
 func (x *SlimArray) GetRank() []uint64 {
 	if x != nil {
 		return x.Rank
@@ -142,6 +168,9 @@ func (x *SlimArray) GetResiduals() []uint64 {
 // Internally it use a SlimArray to store record positions.
 // Thus the memory overhead is about 8 bit / record.
 //
+// If the total size of records exceeds 4 GB, positions are stored in a 64-bit
+// SlimArray.
+//
 // Since 0.1.4
 type SlimBytes struct {
 	state         protoimpl.MessageState
@@ -149,7 +178,7 @@ type SlimBytes struct {
 	unknownFields protoimpl.UnknownFields
 
 	// Positions is the array of start position of every record.
-	// There are n + 1 int32 in it.
+	// There are n + 1 uint32(or uint64 if Positions.EltWidth is 64) in it.
 	// The last one equals len(Records)
 	Positions *SlimArray `protobuf:"bytes,21,opt,name=Positions,proto3" json:"Positions,omitempty"`
 	// Records is byte slice of all record packed together.
@@ -202,75 +231,999 @@ func (x *SlimBytes) GetRecords() []byte {
 	return nil
 }
 
-var File_slimarray_proto protoreflect.FileDescriptor
+// SlimMap is a static map of []byte key to []byte value.
+//
+// Keys are sorted and stored in a SlimBytes, values are stored in another
+// SlimBytes in the same order of keys.
+// A lookup is a binary search on Keys.
+//
+// Since 0.1.15
+type SlimMap struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
 
-var file_slimarray_proto_rawDesc = []byte{
-	0x0a, 0x0f, 0x73, 0x6c, 0x69, 0x6d, 0x61, 0x72, 0x72, 0x61, 0x79, 0x2e, 0x70, 0x72, 0x6f, 0x74,
-	0x6f, 0x22, 0x9f, 0x01, 0x0a, 0x09, 0x53, 0x6c, 0x69, 0x6d, 0x41, 0x72, 0x72, 0x61, 0x79, 0x12,
-	0x0c, 0x0a, 0x01, 0x4e, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x05, 0x52, 0x01, 0x4e, 0x12, 0x12, 0x0a,
-	0x04, 0x52, 0x61, 0x6e, 0x6b, 0x18, 0x13, 0x20, 0x03, 0x28, 0x04, 0x52, 0x04, 0x52, 0x61, 0x6e,
-	0x6b, 0x12, 0x16, 0x0a, 0x06, 0x42, 0x69, 0x74, 0x6d, 0x61, 0x70, 0x18, 0x14, 0x20, 0x03, 0x28,
-	0x04, 0x52, 0x06, 0x42, 0x69, 0x74, 0x6d, 0x61, 0x70, 0x12, 0x20, 0x0a, 0x0b, 0x50, 0x6f, 0x6c,
-	0x79, 0x6e, 0x6f, 0x6d, 0x69, 0x61, 0x6c, 0x73, 0x18, 0x15, 0x20, 0x03, 0x28, 0x01, 0x52, 0x0b,
-	0x50, 0x6f, 0x6c, 0x79, 0x6e, 0x6f, 0x6d, 0x69, 0x61, 0x6c, 0x73, 0x12, 0x18, 0x0a, 0x07, 0x43,
-	0x6f, 0x6e, 0x66, 0x69, 0x67, 0x73, 0x18, 0x16, 0x20, 0x03, 0x28, 0x03, 0x52, 0x07, 0x43, 0x6f,
-	0x6e, 0x66, 0x69, 0x67, 0x73, 0x12, 0x1c, 0x0a, 0x09, 0x52, 0x65, 0x73, 0x69, 0x64, 0x75, 0x61,
-	0x6c, 0x73, 0x18, 0x17, 0x20, 0x03, 0x28, 0x04, 0x52, 0x09, 0x52, 0x65, 0x73, 0x69, 0x64, 0x75,
-	0x61, 0x6c, 0x73, 0x22, 0x4f, 0x0a, 0x09, 0x53, 0x6c, 0x69, 0x6d, 0x42, 0x79, 0x74, 0x65, 0x73,
-	0x12, 0x28, 0x0a, 0x09, 0x50, 0x6f, 0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x15, 0x20,
-	0x01, 0x28, 0x0b, 0x32, 0x0a, 0x2e, 0x53, 0x6c, 0x69, 0x6d, 0x41, 0x72, 0x72, 0x61, 0x79, 0x52,
-	0x09, 0x50, 0x6f, 0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x18, 0x0a, 0x07, 0x52, 0x65,
-	0x63, 0x6f, 0x72, 0x64, 0x73, 0x18, 0x16, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x07, 0x52, 0x65, 0x63,
-	0x6f, 0x72, 0x64, 0x73, 0x42, 0x0b, 0x5a, 0x09, 0x73, 0x6c, 0x69, 0x6d, 0x61, 0x72, 0x72, 0x61,
-	0x79, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	// Keys are sorted keys.
+	Keys *SlimBytes `protobuf:"bytes,21,opt,name=Keys,proto3" json:"Keys,omitempty"`
+	// Values[i] is the value of Keys[i].
+	Values *SlimBytes `protobuf:"bytes,22,opt,name=Values,proto3" json:"Values,omitempty"`
 }
 
-var (
-	file_slimarray_proto_rawDescOnce sync.Once
-	file_slimarray_proto_rawDescData = file_slimarray_proto_rawDesc
-)
+func (x *SlimMap) Reset() {
+	*x = SlimMap{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_slimarray_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
 
-func file_slimarray_proto_rawDescGZIP() []byte {
-	file_slimarray_proto_rawDescOnce.Do(func() {
-		file_slimarray_proto_rawDescData = protoimpl.X.CompressGZIP(file_slimarray_proto_rawDescData)
-	})
-	return file_slimarray_proto_rawDescData
+func (x *SlimMap) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
 
-var file_slimarray_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
-var file_slimarray_proto_goTypes = []interface{}{
-	(*SlimArray)(nil), // 0: SlimArray
-	(*SlimBytes)(nil), // 1: SlimBytes
+func (*SlimMap) ProtoMessage() {}
+
+func (x *SlimMap) ProtoReflect() protoreflect.Message {
+	mi := &file_slimarray_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
 }
-var file_slimarray_proto_depIdxs = []int32{
-	0, // 0: SlimBytes.Positions:type_name -> SlimArray
-	1, // [1:1] is the sub-list for method output_type
-	1, // [1:1] is the sub-list for method input_type
-	1, // [1:1] is the sub-list for extension type_name
-	1, // [1:1] is the sub-list for extension extendee
-	0, // [0:1] is the sub-list for field type_name
+
+// Deprecated: Use SlimMap.ProtoReflect.Descriptor instead.
+func (*SlimMap) Descriptor() ([]byte, []int) {
+	return file_slimarray_proto_rawDescGZIP(), []int{2}
 }
 
-func init() { file_slimarray_proto_init() }
-func file_slimarray_proto_init() {
-	if File_slimarray_proto != nil {
-		return
+func (x *SlimMap) GetKeys() *SlimBytes {
+	if x != nil {
+		return x.Keys
 	}
-	if !protoimpl.UnsafeEnabled {
-		file_slimarray_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*SlimArray); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
+	return nil
+}
+
+func (x *SlimMap) GetValues() *SlimBytes {
+	if x != nil {
+		return x.Values
+	}
+	return nil
+}
+
+// SortedBytes is a front-coded array of sorted []byte.
+//
+// Records are grouped into blocks of BlockSize records.
+// The first record in a block is stored in full.
+// Every other record is stored as the length of prefix shared with the
+// preceding record in uvarint, followed by the rest suffix.
+//
+// Since 0.1.15
+type SortedBytes struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// BlockSize is the number of records in a block.
+	BlockSize int32 `protobuf:"varint,20,opt,name=BlockSize,proto3" json:"BlockSize,omitempty"`
+	// Entries are the encoded records.
+	Entries *SlimBytes `protobuf:"bytes,21,opt,name=Entries,proto3" json:"Entries,omitempty"`
+}
+
+func (x *SortedBytes) Reset() {
+	*x = SortedBytes{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_slimarray_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SortedBytes) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SortedBytes) ProtoMessage() {}
+
+func (x *SortedBytes) ProtoReflect() protoreflect.Message {
+	mi := &file_slimarray_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
 		}
-		file_slimarray_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*SlimBytes); i {
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SortedBytes.ProtoReflect.Descriptor instead.
+func (*SortedBytes) Descriptor() ([]byte, []int) {
+	return file_slimarray_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *SortedBytes) GetBlockSize() int32 {
+	if x != nil {
+		return x.BlockSize
+	}
+	return 0
+}
+
+func (x *SortedBytes) GetEntries() *SlimBytes {
+	if x != nil {
+		return x.Entries
+	}
+	return nil
+}
+
+// CompressedBytes is a var-length []byte array in which records are grouped
+// into blocks and every block is compressed.
+//
+// Since 0.1.15
+type CompressedBytes struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Codec is the name of the registered codec to decompress a block.
+	Codec string `protobuf:"bytes,20,opt,name=Codec,proto3" json:"Codec,omitempty"`
+	// Positions is the uncompressed position of every record.
+	// There are n + 1 uint64 in it.
+	Positions *SlimArray `protobuf:"bytes,21,opt,name=Positions,proto3" json:"Positions,omitempty"`
+	// Blocks is the index of the block a record is in.
+	Blocks *SlimArray `protobuf:"bytes,22,opt,name=Blocks,proto3" json:"Blocks,omitempty"`
+	// BlockStarts is the uncompressed position of every block.
+	// There are nBlocks + 1 uint64 in it.
+	BlockStarts *SlimArray `protobuf:"bytes,23,opt,name=BlockStarts,proto3" json:"BlockStarts,omitempty"`
+	// BlockOffsets is the offset of every compressed block in Data.
+	// There are nBlocks + 1 uint64 in it.
+	BlockOffsets *SlimArray `protobuf:"bytes,24,opt,name=BlockOffsets,proto3" json:"BlockOffsets,omitempty"`
+	// Data is all compressed blocks packed together.
+	Data []byte `protobuf:"bytes,25,opt,name=Data,proto3" json:"Data,omitempty"`
+}
+
+func (x *CompressedBytes) Reset() {
+	*x = CompressedBytes{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_slimarray_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CompressedBytes) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CompressedBytes) ProtoMessage() {}
+
+func (x *CompressedBytes) ProtoReflect() protoreflect.Message {
+	mi := &file_slimarray_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CompressedBytes.ProtoReflect.Descriptor instead.
+func (*CompressedBytes) Descriptor() ([]byte, []int) {
+	return file_slimarray_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *CompressedBytes) GetCodec() string {
+	if x != nil {
+		return x.Codec
+	}
+	return ""
+}
+
+func (x *CompressedBytes) GetPositions() *SlimArray {
+	if x != nil {
+		return x.Positions
+	}
+	return nil
+}
+
+func (x *CompressedBytes) GetBlocks() *SlimArray {
+	if x != nil {
+		return x.Blocks
+	}
+	return nil
+}
+
+func (x *CompressedBytes) GetBlockStarts() *SlimArray {
+	if x != nil {
+		return x.BlockStarts
+	}
+	return nil
+}
+
+func (x *CompressedBytes) GetBlockOffsets() *SlimArray {
+	if x != nil {
+		return x.BlockOffsets
+	}
+	return nil
+}
+
+func (x *CompressedBytes) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+// SlimF64 is a float64 array compressed with SlimArray.
+//
+// A lossless SlimF64 maps every float to an order-preserving uint64 key, and
+// stores the high 32 bits and the low 32 bits of keys in two SlimArray.
+//
+// A lossless SlimF64 of float32 maps every float to an order-preserving uint32
+// key, and stores keys in one SlimArray.
+//
+// A lossy SlimF64 quantizes every float x to an integer
+// q = round((x - Base) / (2 * MaxErr)), and stores q in a SlimArray.
+//
+// Since 0.1.15
+type SlimF64 struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// MaxErr is the max absolute error of an elt.
+	// It is 0 for a lossless SlimF64.
+	MaxErr float64 `protobuf:"fixed64,20,opt,name=MaxErr,proto3" json:"MaxErr,omitempty"`
+	// Base is the value quantized to 0 in a lossy SlimF64.
+	Base float64 `protobuf:"fixed64,21,opt,name=Base,proto3" json:"Base,omitempty"`
+	// Quantized is the quantized elts in a lossy SlimF64.
+	Quantized *SlimArray `protobuf:"bytes,22,opt,name=Quantized,proto3" json:"Quantized,omitempty"`
+	// High is the high 32 bits of keys in a lossless SlimF64.
+	High *SlimArray `protobuf:"bytes,23,opt,name=High,proto3" json:"High,omitempty"`
+	// Low is the low 32 bits of keys in a lossless SlimF64.
+	Low *SlimArray `protobuf:"bytes,24,opt,name=Low,proto3" json:"Low,omitempty"`
+	// F32Keys is the keys of float32 in a lossless SlimF64 created by NewF32.
+	F32Keys *SlimArray `protobuf:"bytes,25,opt,name=F32Keys,proto3" json:"F32Keys,omitempty"`
+}
+
+func (x *SlimF64) Reset() {
+	*x = SlimF64{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_slimarray_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SlimF64) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SlimF64) ProtoMessage() {}
+
+func (x *SlimF64) ProtoReflect() protoreflect.Message {
+	mi := &file_slimarray_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SlimF64.ProtoReflect.Descriptor instead.
+func (*SlimF64) Descriptor() ([]byte, []int) {
+	return file_slimarray_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *SlimF64) GetMaxErr() float64 {
+	if x != nil {
+		return x.MaxErr
+	}
+	return 0
+}
+
+func (x *SlimF64) GetBase() float64 {
+	if x != nil {
+		return x.Base
+	}
+	return 0
+}
+
+func (x *SlimF64) GetQuantized() *SlimArray {
+	if x != nil {
+		return x.Quantized
+	}
+	return nil
+}
+
+func (x *SlimF64) GetHigh() *SlimArray {
+	if x != nil {
+		return x.High
+	}
+	return nil
+}
+
+func (x *SlimF64) GetLow() *SlimArray {
+	if x != nil {
+		return x.Low
+	}
+	return nil
+}
+
+func (x *SlimF64) GetF32Keys() *SlimArray {
+	if x != nil {
+		return x.F32Keys
+	}
+	return nil
+}
+
+// SlimTimes is a sorted array of timestamps.
+//
+// A timestamp t is stored as the number of Resolution since Epoch, i.e.,
+// (t - Epoch) / Resolution, in a 64-bit SlimArray.
+//
+// Since 0.1.15
+type SlimTimes struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Epoch is the base time in unix nano seconds.
+	Epoch int64 `protobuf:"varint,20,opt,name=Epoch,proto3" json:"Epoch,omitempty"`
+	// Resolution is the unit of stored timestamps in nano seconds, e.g., 1000
+	// for micro second.
+	Resolution int64 `protobuf:"varint,21,opt,name=Resolution,proto3" json:"Resolution,omitempty"`
+	// Times is the number of Resolution since Epoch of every timestamp.
+	Times *SlimArray `protobuf:"bytes,22,opt,name=Times,proto3" json:"Times,omitempty"`
+}
+
+func (x *SlimTimes) Reset() {
+	*x = SlimTimes{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_slimarray_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SlimTimes) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SlimTimes) ProtoMessage() {}
+
+func (x *SlimTimes) ProtoReflect() protoreflect.Message {
+	mi := &file_slimarray_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SlimTimes.ProtoReflect.Descriptor instead.
+func (*SlimTimes) Descriptor() ([]byte, []int) {
+	return file_slimarray_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *SlimTimes) GetEpoch() int64 {
+	if x != nil {
+		return x.Epoch
+	}
+	return 0
+}
+
+func (x *SlimTimes) GetResolution() int64 {
+	if x != nil {
+		return x.Resolution
+	}
+	return 0
+}
+
+func (x *SlimTimes) GetTimes() *SlimArray {
+	if x != nil {
+		return x.Times
+	}
+	return nil
+}
+
+// IPSet is a set of IPv4 and IPv6 addresses, stored as sorted non-overlapping
+// ranges.
+// A range is described by its first and last address.
+//
+// An IPv6 address is split into 4 32-bit lanes, the i-th lane is stored in
+// the i-th SlimArray of V6Starts or V6Ends.
+//
+// Since 0.1.15
+type IPSet struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// V4Starts is the first address of every IPv4 range.
+	V4Starts *SlimArray `protobuf:"bytes,20,opt,name=V4Starts,proto3" json:"V4Starts,omitempty"`
+	// V4Ends is the last address of every IPv4 range.
+	V4Ends *SlimArray `protobuf:"bytes,21,opt,name=V4Ends,proto3" json:"V4Ends,omitempty"`
+	// V6Starts is 4 lanes of the first address of every IPv6 range.
+	V6Starts []*SlimArray `protobuf:"bytes,22,rep,name=V6Starts,proto3" json:"V6Starts,omitempty"`
+	// V6Ends is 4 lanes of the last address of every IPv6 range.
+	V6Ends []*SlimArray `protobuf:"bytes,23,rep,name=V6Ends,proto3" json:"V6Ends,omitempty"`
+}
+
+func (x *IPSet) Reset() {
+	*x = IPSet{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_slimarray_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *IPSet) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IPSet) ProtoMessage() {}
+
+func (x *IPSet) ProtoReflect() protoreflect.Message {
+	mi := &file_slimarray_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IPSet.ProtoReflect.Descriptor instead.
+func (*IPSet) Descriptor() ([]byte, []int) {
+	return file_slimarray_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *IPSet) GetV4Starts() *SlimArray {
+	if x != nil {
+		return x.V4Starts
+	}
+	return nil
+}
+
+func (x *IPSet) GetV4Ends() *SlimArray {
+	if x != nil {
+		return x.V4Ends
+	}
+	return nil
+}
+
+func (x *IPSet) GetV6Starts() []*SlimArray {
+	if x != nil {
+		return x.V6Starts
+	}
+	return nil
+}
+
+func (x *IPSet) GetV6Ends() []*SlimArray {
+	if x != nil {
+		return x.V6Ends
+	}
+	return nil
+}
+
+// SlimGraph is a static directed graph in compressed sparse row format.
+//
+// Vertices are numbered from 0 to n-1.
+// Neighbors of every vertex are sorted and packed together in Targets.
+//
+// Since 0.1.15
+type SlimGraph struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Offsets[v] is the index of the first edge of vertex v in Targets.
+	// There are n + 1 uint32 in it. The last one is the number of edges.
+	Offsets *SlimArray `protobuf:"bytes,20,opt,name=Offsets,proto3" json:"Offsets,omitempty"`
+	// Targets is the sorted target vertices of edges of every vertex.
+	Targets *SlimArray `protobuf:"bytes,21,opt,name=Targets,proto3" json:"Targets,omitempty"`
+}
+
+func (x *SlimGraph) Reset() {
+	*x = SlimGraph{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_slimarray_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SlimGraph) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SlimGraph) ProtoMessage() {}
+
+func (x *SlimGraph) ProtoReflect() protoreflect.Message {
+	mi := &file_slimarray_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SlimGraph.ProtoReflect.Descriptor instead.
+func (*SlimGraph) Descriptor() ([]byte, []int) {
+	return file_slimarray_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *SlimGraph) GetOffsets() *SlimArray {
+	if x != nil {
+		return x.Offsets
+	}
+	return nil
+}
+
+func (x *SlimGraph) GetTargets() *SlimArray {
+	if x != nil {
+		return x.Targets
+	}
+	return nil
+}
+
+// TableColumn is a column of SlimTable.
+//
+// Since 0.1.15
+type TableColumn struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Name is the unique name of the column in a table.
+	Name string `protobuf:"bytes,20,opt,name=Name,proto3" json:"Name,omitempty"`
+	// Type is one of ColumnU32, ColumnI64 or ColumnBytes.
+	Type int32 `protobuf:"varint,21,opt,name=Type,proto3" json:"Type,omitempty"`
+	// Base is the minimal value of an int64 column.
+	// An int64 value v is stored as uint64(v - Base).
+	Base int64 `protobuf:"varint,22,opt,name=Base,proto3" json:"Base,omitempty"`
+	// Nums stores values of a uint32 or int64 column.
+	Nums *SlimArray `protobuf:"bytes,23,opt,name=Nums,proto3" json:"Nums,omitempty"`
+	// Bytes stores values of a []byte column.
+	Bytes *SlimBytes `protobuf:"bytes,24,opt,name=Bytes,proto3" json:"Bytes,omitempty"`
+}
+
+func (x *TableColumn) Reset() {
+	*x = TableColumn{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_slimarray_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TableColumn) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TableColumn) ProtoMessage() {}
+
+func (x *TableColumn) ProtoReflect() protoreflect.Message {
+	mi := &file_slimarray_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TableColumn.ProtoReflect.Descriptor instead.
+func (*TableColumn) Descriptor() ([]byte, []int) {
+	return file_slimarray_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *TableColumn) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *TableColumn) GetType() int32 {
+	if x != nil {
+		return x.Type
+	}
+	return 0
+}
+
+func (x *TableColumn) GetBase() int64 {
+	if x != nil {
+		return x.Base
+	}
+	return 0
+}
+
+func (x *TableColumn) GetNums() *SlimArray {
+	if x != nil {
+		return x.Nums
+	}
+	return nil
+}
+
+func (x *TableColumn) GetBytes() *SlimBytes {
+	if x != nil {
+		return x.Bytes
+	}
+	return nil
+}
+
+// SlimTable is a static table of rows with typed columns.
+// Values of every column are stored in a SlimArray or a SlimBytes.
+//
+// Rows could be sorted by one column, to search rows by the value of this
+// column.
+//
+// Since 0.1.15
+type SlimTable struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Columns are columns in schema order.
+	Columns []*TableColumn `protobuf:"bytes,20,rep,name=Columns,proto3" json:"Columns,omitempty"`
+	// SortedBy is the name of the column rows are sorted by.
+	// It is empty if rows are not sorted.
+	SortedBy string `protobuf:"bytes,21,opt,name=SortedBy,proto3" json:"SortedBy,omitempty"`
+}
+
+func (x *SlimTable) Reset() {
+	*x = SlimTable{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_slimarray_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SlimTable) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SlimTable) ProtoMessage() {}
+
+func (x *SlimTable) ProtoReflect() protoreflect.Message {
+	mi := &file_slimarray_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SlimTable.ProtoReflect.Descriptor instead.
+func (*SlimTable) Descriptor() ([]byte, []int) {
+	return file_slimarray_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *SlimTable) GetColumns() []*TableColumn {
+	if x != nil {
+		return x.Columns
+	}
+	return nil
+}
+
+func (x *SlimTable) GetSortedBy() string {
+	if x != nil {
+		return x.SortedBy
+	}
+	return ""
+}
+
+var File_slimarray_proto protoreflect.FileDescriptor
+
+var file_slimarray_proto_rawDesc = []byte{
+	0x0a, 0x0f, 0x73, 0x6c, 0x69, 0x6d, 0x61, 0x72, 0x72, 0x61, 0x79, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x22, 0xd3, 0x01, 0x0a, 0x09, 0x53, 0x6c, 0x69, 0x6d, 0x41, 0x72, 0x72, 0x61, 0x79, 0x12,
+	0x0c, 0x0a, 0x01, 0x4e, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x05, 0x52, 0x01, 0x4e, 0x12, 0x1a, 0x0a,
+	0x08, 0x45, 0x6c, 0x74, 0x57, 0x69, 0x64, 0x74, 0x68, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x05, 0x52,
+	0x08, 0x45, 0x6c, 0x74, 0x57, 0x69, 0x64, 0x74, 0x68, 0x12, 0x16, 0x0a, 0x06, 0x4d, 0x61, 0x78,
+	0x45, 0x72, 0x72, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x06, 0x4d, 0x61, 0x78, 0x45, 0x72,
+	0x72, 0x12, 0x12, 0x0a, 0x04, 0x52, 0x61, 0x6e, 0x6b, 0x18, 0x13, 0x20, 0x03, 0x28, 0x04, 0x52,
+	0x04, 0x52, 0x61, 0x6e, 0x6b, 0x12, 0x16, 0x0a, 0x06, 0x42, 0x69, 0x74, 0x6d, 0x61, 0x70, 0x18,
+	0x14, 0x20, 0x03, 0x28, 0x04, 0x52, 0x06, 0x42, 0x69, 0x74, 0x6d, 0x61, 0x70, 0x12, 0x20, 0x0a,
+	0x0b, 0x50, 0x6f, 0x6c, 0x79, 0x6e, 0x6f, 0x6d, 0x69, 0x61, 0x6c, 0x73, 0x18, 0x15, 0x20, 0x03,
+	0x28, 0x01, 0x52, 0x0b, 0x50, 0x6f, 0x6c, 0x79, 0x6e, 0x6f, 0x6d, 0x69, 0x61, 0x6c, 0x73, 0x12,
+	0x18, 0x0a, 0x07, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x73, 0x18, 0x16, 0x20, 0x03, 0x28, 0x03,
+	0x52, 0x07, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x73, 0x12, 0x1c, 0x0a, 0x09, 0x52, 0x65, 0x73,
+	0x69, 0x64, 0x75, 0x61, 0x6c, 0x73, 0x18, 0x17, 0x20, 0x03, 0x28, 0x04, 0x52, 0x09, 0x52, 0x65,
+	0x73, 0x69, 0x64, 0x75, 0x61, 0x6c, 0x73, 0x22, 0x4f, 0x0a, 0x09, 0x53, 0x6c, 0x69, 0x6d, 0x42,
+	0x79, 0x74, 0x65, 0x73, 0x12, 0x28, 0x0a, 0x09, 0x50, 0x6f, 0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e,
+	0x73, 0x18, 0x15, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0a, 0x2e, 0x53, 0x6c, 0x69, 0x6d, 0x41, 0x72,
+	0x72, 0x61, 0x79, 0x52, 0x09, 0x50, 0x6f, 0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x18,
+	0x0a, 0x07, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x73, 0x18, 0x16, 0x20, 0x01, 0x28, 0x0c, 0x52,
+	0x07, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x73, 0x22, 0x4d, 0x0a, 0x07, 0x53, 0x6c, 0x69, 0x6d,
+	0x4d, 0x61, 0x70, 0x12, 0x1e, 0x0a, 0x04, 0x4b, 0x65, 0x79, 0x73, 0x18, 0x15, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x0a, 0x2e, 0x53, 0x6c, 0x69, 0x6d, 0x42, 0x79, 0x74, 0x65, 0x73, 0x52, 0x04, 0x4b,
+	0x65, 0x79, 0x73, 0x12, 0x22, 0x0a, 0x06, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x73, 0x18, 0x16, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x0a, 0x2e, 0x53, 0x6c, 0x69, 0x6d, 0x42, 0x79, 0x74, 0x65, 0x73, 0x52,
+	0x06, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x73, 0x22, 0x51, 0x0a, 0x0b, 0x53, 0x6f, 0x72, 0x74, 0x65,
+	0x64, 0x42, 0x79, 0x74, 0x65, 0x73, 0x12, 0x1c, 0x0a, 0x09, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x53,
+	0x69, 0x7a, 0x65, 0x18, 0x14, 0x20, 0x01, 0x28, 0x05, 0x52, 0x09, 0x42, 0x6c, 0x6f, 0x63, 0x6b,
+	0x53, 0x69, 0x7a, 0x65, 0x12, 0x24, 0x0a, 0x07, 0x45, 0x6e, 0x74, 0x72, 0x69, 0x65, 0x73, 0x18,
+	0x15, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0a, 0x2e, 0x53, 0x6c, 0x69, 0x6d, 0x42, 0x79, 0x74, 0x65,
+	0x73, 0x52, 0x07, 0x45, 0x6e, 0x74, 0x72, 0x69, 0x65, 0x73, 0x22, 0xe7, 0x01, 0x0a, 0x0f, 0x43,
+	0x6f, 0x6d, 0x70, 0x72, 0x65, 0x73, 0x73, 0x65, 0x64, 0x42, 0x79, 0x74, 0x65, 0x73, 0x12, 0x14,
+	0x0a, 0x05, 0x43, 0x6f, 0x64, 0x65, 0x63, 0x18, 0x14, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x43,
+	0x6f, 0x64, 0x65, 0x63, 0x12, 0x28, 0x0a, 0x09, 0x50, 0x6f, 0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e,
+	0x73, 0x18, 0x15, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0a, 0x2e, 0x53, 0x6c, 0x69, 0x6d, 0x41, 0x72,
+	0x72, 0x61, 0x79, 0x52, 0x09, 0x50, 0x6f, 0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x22,
+	0x0a, 0x06, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x73, 0x18, 0x16, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0a,
+	0x2e, 0x53, 0x6c, 0x69, 0x6d, 0x41, 0x72, 0x72, 0x61, 0x79, 0x52, 0x06, 0x42, 0x6c, 0x6f, 0x63,
+	0x6b, 0x73, 0x12, 0x2c, 0x0a, 0x0b, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x53, 0x74, 0x61, 0x72, 0x74,
+	0x73, 0x18, 0x17, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0a, 0x2e, 0x53, 0x6c, 0x69, 0x6d, 0x41, 0x72,
+	0x72, 0x61, 0x79, 0x52, 0x0b, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x53, 0x74, 0x61, 0x72, 0x74, 0x73,
+	0x12, 0x2e, 0x0a, 0x0c, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x4f, 0x66, 0x66, 0x73, 0x65, 0x74, 0x73,
+	0x18, 0x18, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0a, 0x2e, 0x53, 0x6c, 0x69, 0x6d, 0x41, 0x72, 0x72,
+	0x61, 0x79, 0x52, 0x0c, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x4f, 0x66, 0x66, 0x73, 0x65, 0x74, 0x73,
+	0x12, 0x12, 0x0a, 0x04, 0x44, 0x61, 0x74, 0x61, 0x18, 0x19, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x04,
+	0x44, 0x61, 0x74, 0x61, 0x22, 0xc3, 0x01, 0x0a, 0x07, 0x53, 0x6c, 0x69, 0x6d, 0x46, 0x36, 0x34,
+	0x12, 0x16, 0x0a, 0x06, 0x4d, 0x61, 0x78, 0x45, 0x72, 0x72, 0x18, 0x14, 0x20, 0x01, 0x28, 0x01,
+	0x52, 0x06, 0x4d, 0x61, 0x78, 0x45, 0x72, 0x72, 0x12, 0x12, 0x0a, 0x04, 0x42, 0x61, 0x73, 0x65,
+	0x18, 0x15, 0x20, 0x01, 0x28, 0x01, 0x52, 0x04, 0x42, 0x61, 0x73, 0x65, 0x12, 0x28, 0x0a, 0x09,
+	0x51, 0x75, 0x61, 0x6e, 0x74, 0x69, 0x7a, 0x65, 0x64, 0x18, 0x16, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x0a, 0x2e, 0x53, 0x6c, 0x69, 0x6d, 0x41, 0x72, 0x72, 0x61, 0x79, 0x52, 0x09, 0x51, 0x75, 0x61,
+	0x6e, 0x74, 0x69, 0x7a, 0x65, 0x64, 0x12, 0x1e, 0x0a, 0x04, 0x48, 0x69, 0x67, 0x68, 0x18, 0x17,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x0a, 0x2e, 0x53, 0x6c, 0x69, 0x6d, 0x41, 0x72, 0x72, 0x61, 0x79,
+	0x52, 0x04, 0x48, 0x69, 0x67, 0x68, 0x12, 0x1c, 0x0a, 0x03, 0x4c, 0x6f, 0x77, 0x18, 0x18, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x0a, 0x2e, 0x53, 0x6c, 0x69, 0x6d, 0x41, 0x72, 0x72, 0x61, 0x79, 0x52,
+	0x03, 0x4c, 0x6f, 0x77, 0x12, 0x24, 0x0a, 0x07, 0x46, 0x33, 0x32, 0x4b, 0x65, 0x79, 0x73, 0x18,
+	0x19, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0a, 0x2e, 0x53, 0x6c, 0x69, 0x6d, 0x41, 0x72, 0x72, 0x61,
+	0x79, 0x52, 0x07, 0x46, 0x33, 0x32, 0x4b, 0x65, 0x79, 0x73, 0x22, 0x63, 0x0a, 0x09, 0x53, 0x6c,
+	0x69, 0x6d, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x45, 0x70, 0x6f, 0x63, 0x68,
+	0x18, 0x14, 0x20, 0x01, 0x28, 0x03, 0x52, 0x05, 0x45, 0x70, 0x6f, 0x63, 0x68, 0x12, 0x1e, 0x0a,
+	0x0a, 0x52, 0x65, 0x73, 0x6f, 0x6c, 0x75, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x15, 0x20, 0x01, 0x28,
+	0x03, 0x52, 0x0a, 0x52, 0x65, 0x73, 0x6f, 0x6c, 0x75, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x20, 0x0a,
+	0x05, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x18, 0x16, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0a, 0x2e, 0x53,
+	0x6c, 0x69, 0x6d, 0x41, 0x72, 0x72, 0x61, 0x79, 0x52, 0x05, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x22,
+	0x9f, 0x01, 0x0a, 0x05, 0x49, 0x50, 0x53, 0x65, 0x74, 0x12, 0x26, 0x0a, 0x08, 0x56, 0x34, 0x53,
+	0x74, 0x61, 0x72, 0x74, 0x73, 0x18, 0x14, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0a, 0x2e, 0x53, 0x6c,
+	0x69, 0x6d, 0x41, 0x72, 0x72, 0x61, 0x79, 0x52, 0x08, 0x56, 0x34, 0x53, 0x74, 0x61, 0x72, 0x74,
+	0x73, 0x12, 0x22, 0x0a, 0x06, 0x56, 0x34, 0x45, 0x6e, 0x64, 0x73, 0x18, 0x15, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x0a, 0x2e, 0x53, 0x6c, 0x69, 0x6d, 0x41, 0x72, 0x72, 0x61, 0x79, 0x52, 0x06, 0x56,
+	0x34, 0x45, 0x6e, 0x64, 0x73, 0x12, 0x26, 0x0a, 0x08, 0x56, 0x36, 0x53, 0x74, 0x61, 0x72, 0x74,
+	0x73, 0x18, 0x16, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0a, 0x2e, 0x53, 0x6c, 0x69, 0x6d, 0x41, 0x72,
+	0x72, 0x61, 0x79, 0x52, 0x08, 0x56, 0x36, 0x53, 0x74, 0x61, 0x72, 0x74, 0x73, 0x12, 0x22, 0x0a,
+	0x06, 0x56, 0x36, 0x45, 0x6e, 0x64, 0x73, 0x18, 0x17, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0a, 0x2e,
+	0x53, 0x6c, 0x69, 0x6d, 0x41, 0x72, 0x72, 0x61, 0x79, 0x52, 0x06, 0x56, 0x36, 0x45, 0x6e, 0x64,
+	0x73, 0x22, 0x57, 0x0a, 0x09, 0x53, 0x6c, 0x69, 0x6d, 0x47, 0x72, 0x61, 0x70, 0x68, 0x12, 0x24,
+	0x0a, 0x07, 0x4f, 0x66, 0x66, 0x73, 0x65, 0x74, 0x73, 0x18, 0x14, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x0a, 0x2e, 0x53, 0x6c, 0x69, 0x6d, 0x41, 0x72, 0x72, 0x61, 0x79, 0x52, 0x07, 0x4f, 0x66, 0x66,
+	0x73, 0x65, 0x74, 0x73, 0x12, 0x24, 0x0a, 0x07, 0x54, 0x61, 0x72, 0x67, 0x65, 0x74, 0x73, 0x18,
+	0x15, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0a, 0x2e, 0x53, 0x6c, 0x69, 0x6d, 0x41, 0x72, 0x72, 0x61,
+	0x79, 0x52, 0x07, 0x54, 0x61, 0x72, 0x67, 0x65, 0x74, 0x73, 0x22, 0x8b, 0x01, 0x0a, 0x0b, 0x54,
+	0x61, 0x62, 0x6c, 0x65, 0x43, 0x6f, 0x6c, 0x75, 0x6d, 0x6e, 0x12, 0x12, 0x0a, 0x04, 0x4e, 0x61,
+	0x6d, 0x65, 0x18, 0x14, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x12,
+	0x0a, 0x04, 0x54, 0x79, 0x70, 0x65, 0x18, 0x15, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04, 0x54, 0x79,
+	0x70, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x42, 0x61, 0x73, 0x65, 0x18, 0x16, 0x20, 0x01, 0x28, 0x03,
+	0x52, 0x04, 0x42, 0x61, 0x73, 0x65, 0x12, 0x1e, 0x0a, 0x04, 0x4e, 0x75, 0x6d, 0x73, 0x18, 0x17,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x0a, 0x2e, 0x53, 0x6c, 0x69, 0x6d, 0x41, 0x72, 0x72, 0x61, 0x79,
+	0x52, 0x04, 0x4e, 0x75, 0x6d, 0x73, 0x12, 0x20, 0x0a, 0x05, 0x42, 0x79, 0x74, 0x65, 0x73, 0x18,
+	0x18, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0a, 0x2e, 0x53, 0x6c, 0x69, 0x6d, 0x42, 0x79, 0x74, 0x65,
+	0x73, 0x52, 0x05, 0x42, 0x79, 0x74, 0x65, 0x73, 0x22, 0x4f, 0x0a, 0x09, 0x53, 0x6c, 0x69, 0x6d,
+	0x54, 0x61, 0x62, 0x6c, 0x65, 0x12, 0x26, 0x0a, 0x07, 0x43, 0x6f, 0x6c, 0x75, 0x6d, 0x6e, 0x73,
+	0x18, 0x14, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x54, 0x61, 0x62, 0x6c, 0x65, 0x43, 0x6f,
+	0x6c, 0x75, 0x6d, 0x6e, 0x52, 0x07, 0x43, 0x6f, 0x6c, 0x75, 0x6d, 0x6e, 0x73, 0x12, 0x1a, 0x0a,
+	0x08, 0x53, 0x6f, 0x72, 0x74, 0x65, 0x64, 0x42, 0x79, 0x18, 0x15, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x08, 0x53, 0x6f, 0x72, 0x74, 0x65, 0x64, 0x42, 0x79, 0x42, 0x29, 0x5a, 0x27, 0x67, 0x69, 0x74,
+	0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x6f, 0x70, 0x65, 0x6e, 0x61, 0x63, 0x69, 0x64,
+	0x2f, 0x73, 0x6c, 0x69, 0x6d, 0x61, 0x72, 0x72, 0x61, 0x79, 0x3b, 0x73, 0x6c, 0x69, 0x6d, 0x61,
+	0x72, 0x72, 0x61, 0x79, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_slimarray_proto_rawDescOnce sync.Once
+	file_slimarray_proto_rawDescData = file_slimarray_proto_rawDesc
+)
+
+func file_slimarray_proto_rawDescGZIP() []byte {
+	file_slimarray_proto_rawDescOnce.Do(func() {
+		file_slimarray_proto_rawDescData = protoimpl.X.CompressGZIP(file_slimarray_proto_rawDescData)
+	})
+	return file_slimarray_proto_rawDescData
+}
+
+var file_slimarray_proto_msgTypes = make([]protoimpl.MessageInfo, 11)
+var file_slimarray_proto_goTypes = []interface{}{
+	(*SlimArray)(nil),       // 0: SlimArray
+	(*SlimBytes)(nil),       // 1: SlimBytes
+	(*SlimMap)(nil),         // 2: SlimMap
+	(*SortedBytes)(nil),     // 3: SortedBytes
+	(*CompressedBytes)(nil), // 4: CompressedBytes
+	(*SlimF64)(nil),         // 5: SlimF64
+	(*SlimTimes)(nil),       // 6: SlimTimes
+	(*IPSet)(nil),           // 7: IPSet
+	(*SlimGraph)(nil),       // 8: SlimGraph
+	(*TableColumn)(nil),     // 9: TableColumn
+	(*SlimTable)(nil),       // 10: SlimTable
+}
+var file_slimarray_proto_depIdxs = []int32{
+	0,  // 0: SlimBytes.Positions:type_name -> SlimArray
+	1,  // 1: SlimMap.Keys:type_name -> SlimBytes
+	1,  // 2: SlimMap.Values:type_name -> SlimBytes
+	1,  // 3: SortedBytes.Entries:type_name -> SlimBytes
+	0,  // 4: CompressedBytes.Positions:type_name -> SlimArray
+	0,  // 5: CompressedBytes.Blocks:type_name -> SlimArray
+	0,  // 6: CompressedBytes.BlockStarts:type_name -> SlimArray
+	0,  // 7: CompressedBytes.BlockOffsets:type_name -> SlimArray
+	0,  // 8: SlimF64.Quantized:type_name -> SlimArray
+	0,  // 9: SlimF64.High:type_name -> SlimArray
+	0,  // 10: SlimF64.Low:type_name -> SlimArray
+	0,  // 11: SlimF64.F32Keys:type_name -> SlimArray
+	0,  // 12: SlimTimes.Times:type_name -> SlimArray
+	0,  // 13: IPSet.V4Starts:type_name -> SlimArray
+	0,  // 14: IPSet.V4Ends:type_name -> SlimArray
+	0,  // 15: IPSet.V6Starts:type_name -> SlimArray
+	0,  // 16: IPSet.V6Ends:type_name -> SlimArray
+	0,  // 17: SlimGraph.Offsets:type_name -> SlimArray
+	0,  // 18: SlimGraph.Targets:type_name -> SlimArray
+	0,  // 19: TableColumn.Nums:type_name -> SlimArray
+	1,  // 20: TableColumn.Bytes:type_name -> SlimBytes
+	9,  // 21: SlimTable.Columns:type_name -> TableColumn
+	22, // [22:22] is the sub-list for method output_type
+	22, // [22:22] is the sub-list for method input_type
+	22, // [22:22] is the sub-list for extension type_name
+	22, // [22:22] is the sub-list for extension extendee
+	0,  // [0:22] is the sub-list for field type_name
+}
+
+func init() { file_slimarray_proto_init() }
+func file_slimarray_proto_init() {
+	if File_slimarray_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_slimarray_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SlimArray); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_slimarray_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SlimBytes); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_slimarray_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SlimMap); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_slimarray_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SortedBytes); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_slimarray_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CompressedBytes); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_slimarray_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SlimF64); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_slimarray_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SlimTimes); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_slimarray_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*IPSet); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_slimarray_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SlimGraph); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_slimarray_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TableColumn); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_slimarray_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SlimTable); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -288,7 +1241,7 @@ func file_slimarray_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_slimarray_proto_rawDesc,
 			NumEnums:      0,
-			NumMessages:   2,
+			NumMessages:   11,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
@@ -150,6 +150,33 @@ func TestSlimArray_Slice(t *testing.T) {
 
 }
 
+func TestSlimArray_Slice_acrossSegments(t *testing.T) {
+
+	ta := require.New(t)
+
+	// Slice across segments, or ending at a span boundary.
+	nums := make([]uint32, 3*segSize)
+	for i := range nums {
+		nums[i] = uint32(i*i%1000 + i*7)
+	}
+	a := NewU32(nums)
+
+	for _, se := range [][2]int{
+		{0, 16},
+		{0, segSize},
+		{0, len(nums)},
+		{segSize - 1, segSize + 1},
+		{1000, 2999},
+		{15, 2*segSize + 32},
+		{5, 5},
+	} {
+		s, e := se[0], se[1]
+		rst := make([]uint32, e-s)
+		a.Slice(int32(s), int32(e), rst)
+		ta.Equal(nums[s:e], rst, "slice %d-%d", s, e)
+	}
+}
+
 func TestSlimArray_big(t *testing.T) {
 
 	ta := require.New(t)
@@ -240,6 +267,75 @@ func TestSlimArray_largenum(t *testing.T) {
 	testGet(ta, a, ns)
 }
 
+func TestSlimArray_NewU64(t *testing.T) {
+
+	ta := require.New(t)
+
+	rnd := rand.New(rand.NewSource(time.Now().Unix()))
+
+	sorted := func(n int, start, step uint64) []uint64 {
+		ns := make([]uint64, n)
+		v := start
+		for i := range ns {
+			v += uint64(rnd.Int63n(int64(step)))
+			ns[i] = v
+		}
+		return ns
+	}
+
+	random := func(n int, max int64) []uint64 {
+		ns := make([]uint64, n)
+		for i := range ns {
+			ns[i] = uint64(rnd.Int63n(max))
+		}
+		return ns
+	}
+
+	cases := [][]uint64{
+		{},
+		{0},
+		{1 << 62},
+		sorted(100, 0, 100),
+		sorted(1024*10, 1<<32, 1<<20),
+		sorted(1024*10, 1<<60, 1<<20),
+		sorted(1024*10, 1<<62-1<<40, 1<<20),
+		random(1024*10, 1<<40),
+		random(1024*10, 1<<63-1),
+	}
+
+	for _, nums := range cases {
+
+		a := NewU64(nums)
+		ta.Equal(int32(64), a.EltWidth)
+		ta.Equal(len(nums), a.Len())
+
+		for i, n := range nums {
+			r := a.GetU64(int32(i))
+			ta.Equal(n, r, "i=%d expect: %v; but: %v", i, n, r)
+
+			if i < len(nums)-1 {
+				r, rnext := a.Get2U64(int32(i))
+				ta.Equal(n, r, "i=%d expect: %v; but: %v", i, n, r)
+				ta.Equal(nums[i+1], rnext, "i=%d expect: %v; but: %v", i, nums[i+1], rnext)
+			}
+		}
+	}
+}
+
+func TestSlimArray_NewU64_small(t *testing.T) {
+
+	ta := require.New(t)
+
+	n := 1024 * 4
+	nums := make([]uint64, n)
+	for i := 0; i < n; i++ {
+		nums[i] = 1<<40 + uint64(15*i)
+	}
+
+	a := NewU64(nums)
+	ta.True(a.Stat()["bits/elt"] <= 5)
+}
+
 func TestSlimArray_Get_panic(t *testing.T) {
 	ta := require.New(t)
 
@@ -2,16 +2,28 @@ package slimarray
 
 import (
 	"errors"
+	"io"
 )
 
 var (
+	// BytesTooLarge is no longer returned since 0.1.15:
+	// SlimBytes stores positions in a 64-bit SlimArray if total bytes exceeds
+	// max value of uint32.
 	BytesTooLarge = errors.New("total bytes exceeds max value of uint32")
 	TooManyRows   = errors.New("row count exceeds max value of int32")
+
+	// NegativeSize is returned if a record size is less than 0.
+	//
+	// Since 0.1.15
+	NegativeSize = errors.New("record size is negative")
 )
 
 // NewBytes creates SlimBytes, which is an array of byte slice,
 // from a series of records.
 //
+// If the total size of records exceeds 4 GB, record positions are stored in a
+// 64-bit SlimArray.
+//
 // Since 0.1.14
 func NewBytes(records [][]byte) (*SlimBytes, error) {
 
@@ -25,11 +37,23 @@ func NewBytes(records [][]byte) (*SlimBytes, error) {
 		return nil, TooManyRows
 	}
 
+	packed := make([]byte, 0, size)
+
 	if size > 0xffffffff {
-		return nil, BytesTooLarge
+		pos := make([]uint64, 0, n+1)
+		for _, rec := range records {
+			pos = append(pos, uint64(len(packed)))
+			packed = append(packed, rec...)
+		}
+		pos = append(pos, uint64(len(packed)))
+
+		bs := &SlimBytes{
+			Positions: NewU64(pos),
+			Records:   packed,
+		}
+		return bs, nil
 	}
 
-	packed := make([]byte, 0, size)
 	pos := make([]uint32, 0, n+1)
 
 	for _, rec := range records {
@@ -47,6 +71,50 @@ func NewBytes(records [][]byte) (*SlimBytes, error) {
 	return bs, nil
 }
 
+// NewBytesIndex creates a SlimBytes that stores only record positions, from
+// the size of every record.
+// Records are stored somewhere else, e.g., in a mmapped file, and are read
+// with GetFrom().
+//
+// It returns NegativeSize if any of sizes is less than 0.
+//
+// Since 0.1.15
+func NewBytesIndex(sizes []int64) (*SlimBytes, error) {
+
+	n := int64(len(sizes))
+	if n > 0x7fffffff {
+		return nil, TooManyRows
+	}
+
+	pos := make([]uint64, 0, n+1)
+	p := uint64(0)
+	for _, s := range sizes {
+		if s < 0 {
+			return nil, NegativeSize
+		}
+		pos = append(pos, p)
+		p += uint64(s)
+	}
+	pos = append(pos, p)
+
+	var posArr *SlimArray
+	if p > 0xffffffff {
+		posArr = NewU64(pos)
+	} else {
+		pos32 := make([]uint32, len(pos))
+		for i, v := range pos {
+			pos32[i] = uint32(v)
+		}
+		posArr = NewU32(pos32)
+	}
+
+	bs := &SlimBytes{
+		Positions: posArr,
+	}
+
+	return bs, nil
+}
+
 // Get the i-th record.
 //
 // A Get costs about 17 ns
@@ -54,7 +122,54 @@ func NewBytes(records [][]byte) (*SlimBytes, error) {
 // Since 0.1.14
 func (b *SlimBytes) Get(i int32) []byte {
 
+	if b.Positions.EltWidth == 64 {
+		byteOffset, byteEnd := b.Positions.Get2U64(i)
+		return b.Records[byteOffset:byteEnd]
+	}
+
 	byteOffset, byteEnd := b.Positions.Get2(i)
 
 	return b.Records[byteOffset:byteEnd]
 }
+
+// Len returns the number of records.
+//
+// Since 0.1.15
+func (b *SlimBytes) Len() int {
+	return b.Positions.Len() - 1
+}
+
+// Position returns the start and end position of the i-th record.
+//
+// Since 0.1.15
+func (b *SlimBytes) Position(i int32) (int64, int64) {
+
+	if b.Positions.EltWidth == 64 {
+		s, e := b.Positions.Get2U64(i)
+		return int64(s), int64(e)
+	}
+
+	s, e := b.Positions.Get2(i)
+	return int64(s), int64(e)
+}
+
+// GetFrom reads the i-th record from r, instead of from b.Records.
+// r is where the records are stored, such as an os.File.
+// The i-th record is read from r at the offset Position(i) returns, i.e., r
+// must present all records as one contiguous byte stream.
+// If records are stored in several chunks, it is the caller's job to
+// concatenate them, e.g., with an io.ReaderAt that maps an offset to a chunk.
+//
+// Since 0.1.15
+func (b *SlimBytes) GetFrom(r io.ReaderAt, i int32) ([]byte, error) {
+
+	s, e := b.Position(i)
+
+	buf := make([]byte, e-s)
+	n, err := r.ReadAt(buf, s)
+	if err != nil && !(err == io.EOF && n == len(buf)) {
+		return nil, err
+	}
+
+	return buf, nil
+}
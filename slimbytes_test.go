@@ -1,6 +1,7 @@
 package slimarray
 
 import (
+	"bytes"
 	"fmt"
 	"testing"
 
@@ -107,6 +108,85 @@ func TestSlimBytes_memoryOverhead(t *testing.T) {
 	}
 }
 
+func TestSlimBytes_largePositions(t *testing.T) {
+
+	ta := require.New(t)
+
+	// 4 MB to 6 MB per record, 1.2 GB - 1.5 GB per 256 records.
+	n := 1024
+	sizes := make([]int64, n)
+	for i := range sizes {
+		sizes[i] = 4<<20 + int64(i%7)<<18
+	}
+
+	sb, err := NewBytesIndex(sizes)
+	ta.NoError(err)
+	ta.Equal(int32(64), sb.Positions.EltWidth)
+
+	r := offsetReader{}
+	p := int64(0)
+	for i, size := range sizes {
+		s, e := sb.Position(int32(i))
+		ta.Equal(p, s)
+		ta.Equal(p+size, e)
+		p = e
+
+		if i%100 == 0 {
+			rec, err := sb.GetFrom(r, int32(i))
+			ta.NoError(err)
+			ta.Equal(int(size), len(rec))
+			ta.Equal(byte(s), rec[0])
+			ta.Equal(byte(e-1), rec[len(rec)-1])
+		}
+	}
+	ta.True(p > 0xffffffff)
+}
+
+func TestSlimBytes_NewBytesIndex(t *testing.T) {
+
+	ta := require.New(t)
+
+	records := testutil.RandBytesSlice(1000, 5, 10)
+	sizes := make([]int64, len(records))
+	packed := []byte{}
+	for i, rec := range records {
+		sizes[i] = int64(len(rec))
+		packed = append(packed, rec...)
+	}
+
+	sb, err := NewBytesIndex(sizes)
+	ta.NoError(err)
+	ta.Equal(int32(0), sb.Positions.EltWidth)
+	ta.Nil(sb.Records)
+
+	for i, rec := range records {
+		got, err := sb.GetFrom(bytes.NewReader(packed), int32(i))
+		ta.NoError(err)
+		ta.Equal(rec, got)
+	}
+
+	sb.Records = packed
+	for i, rec := range records {
+		ta.Equal(rec, sb.Get(int32(i)))
+	}
+
+	_, err = sb.GetFrom(bytes.NewReader(packed[:10]), 100)
+	ta.Error(err)
+
+	_, err = NewBytesIndex([]int64{1, 2, -1, 3})
+	ta.Equal(NegativeSize, err)
+}
+
+// offsetReader reads a byte at offset p as byte(p).
+type offsetReader struct{}
+
+func (r offsetReader) ReadAt(p []byte, off int64) (int, error) {
+	for i := range p {
+		p[i] = byte(off + int64(i))
+	}
+	return len(p), nil
+}
+
 var OutputSlimBytes byte
 
 func BenchmarkSlimBytes_Get(b *testing.B) {
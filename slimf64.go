@@ -0,0 +1,369 @@
+package slimarray
+
+import (
+	"errors"
+	"math"
+)
+
+var (
+	// NotFinite is returned if there is a NaN or Inf in a lossy SlimF64.
+	NotFinite = errors.New("NaN or Inf can not be quantized")
+
+	// MaxErrTooSmall is returned if MaxErr is too small to quantize a float
+	// within MaxErr, e.g., MaxErr is close to the precision of the floats.
+	MaxErrTooSmall = errors.New("max error is too small to quantize floats")
+)
+
+// F64Opt specifies how to build a SlimF64.
+//
+// Since 0.1.15
+type F64Opt struct {
+	// Opt specifies how to build the underlying SlimArray-s.
+	Opt
+
+	// MaxErr is the max absolute error of an elt: |Get(i) - nums[i]| <= MaxErr.
+	// By default it is 0, which builds a lossless SlimF64.
+	MaxErr float64
+}
+
+// NewF64 creates a SlimF64 from a slice of float64.
+// opt could be nil to build a lossless SlimF64.
+//
+// A lossless SlimF64 returns exactly the same bits of every float, including
+// -0, NaN and Inf. The high 32 bits of floats with a trend, i.e., the sign,
+// the exponent and the leading bits of mantissa, compress well.
+// The low 32 bits compress well only if floats have few significant bits, e.g.,
+// integers or multiples of 0.25.
+//
+// Keys are not XOR-ed or delta-encoded with the previous elt: decoding such
+// residuals requires all preceding elts and Get() would not be O(1). The
+// polynomial of a span already works as a delta model: a residual of an
+// order-preserving key is the distance to the trend of its neighbors.
+//
+// A lossy SlimF64 quantizes floats by 2*opt.MaxErr and compresses them as
+// integers. It returns NotFinite if there is a NaN or Inf, or MaxErrTooSmall if
+// a float can not be quantized within MaxErr.
+//
+// Since 0.1.15
+func NewF64(nums []float64, opt *F64Opt) (*SlimF64, error) {
+
+	if int64(len(nums)) > 0x7fffffff {
+		return nil, TooManyRows
+	}
+
+	if opt == nil {
+		opt = &F64Opt{}
+	}
+
+	if opt.MaxErr > 0 {
+		return newF64Lossy(nums, opt)
+	}
+
+	n := len(nums)
+	high := make([]uint32, n)
+	low := make([]uint32, n)
+
+	for i, x := range nums {
+		k := f64ToKey(x)
+		high[i] = uint32(k >> 32)
+		low[i] = uint32(k)
+	}
+
+	f := &SlimF64{
+		High: NewU32Opt(high, &opt.Opt),
+		Low:  NewU32Opt(low, &opt.Opt),
+	}
+
+	return f, nil
+}
+
+// NewF32 creates a SlimF64 from a slice of float32.
+// Elts are retrieved with GetF32() or SliceF32(), or with Get() and Slice() as
+// float64.
+//
+// A lossless SlimF64 of float32 maps every float to an order-preserving uint32
+// key and stores keys in one SlimArray, F32Keys. It returns exactly the same
+// bits of every float32.
+//
+// A lossy SlimF64 of float32 is the same as NewF64() with every float
+// converted to float64. GetF32() may differ from nums[i] by opt.MaxErr plus
+// the rounding error of converting the result to float32.
+//
+// Since 0.1.15
+func NewF32(nums []float32, opt *F64Opt) (*SlimF64, error) {
+
+	if int64(len(nums)) > 0x7fffffff {
+		return nil, TooManyRows
+	}
+
+	if opt == nil {
+		opt = &F64Opt{}
+	}
+
+	if opt.MaxErr > 0 {
+		f64s := make([]float64, len(nums))
+		for i, x := range nums {
+			f64s[i] = float64(x)
+		}
+		return newF64Lossy(f64s, opt)
+	}
+
+	keys := make([]uint32, len(nums))
+	for i, x := range nums {
+		keys[i] = f32ToKey(x)
+	}
+
+	f := &SlimF64{
+		F32Keys: NewU32Opt(keys, &opt.Opt),
+	}
+
+	return f, nil
+}
+
+func newF64Lossy(nums []float64, opt *F64Opt) (*SlimF64, error) {
+
+	f := &SlimF64{
+		MaxErr: opt.MaxErr,
+	}
+
+	if len(nums) == 0 {
+		f.Quantized = NewU32Opt(nil, &opt.Opt)
+		return f, nil
+	}
+
+	min, max := nums[0], nums[0]
+	for _, x := range nums {
+		if math.IsNaN(x) || math.IsInf(x, 0) {
+			return nil, NotFinite
+		}
+		if x < min {
+			min = x
+		}
+		if x > max {
+			max = x
+		}
+	}
+
+	f.Base = min
+
+	// A quantized value must be exactly represented by a float64.
+	step := f.step()
+	if (max-min)/step >= 1<<53 {
+		return nil, MaxErrTooSmall
+	}
+
+	qs := make([]uint64, len(nums))
+	maxQ := uint64(0)
+
+	for i, x := range nums {
+		q, ok := f.quantize(x)
+		if !ok {
+			return nil, MaxErrTooSmall
+		}
+		qs[i] = q
+		if q > maxQ {
+			maxQ = q
+		}
+	}
+
+	if maxQ <= math.MaxUint32 {
+		q32 := make([]uint32, len(qs))
+		for i, q := range qs {
+			q32[i] = uint32(q)
+		}
+		f.Quantized = NewU32Opt(q32, &opt.Opt)
+	} else {
+		f.Quantized = NewU64Opt(qs, &opt.Opt)
+	}
+
+	return f, nil
+}
+
+// Len returns number of elements.
+//
+// Since 0.1.15
+func (f *SlimF64) Len() int {
+	if f.MaxErr > 0 {
+		return f.Quantized.Len()
+	}
+	if f.F32Keys != nil {
+		return f.F32Keys.Len()
+	}
+	return f.High.Len()
+}
+
+// Get returns the i-th float.
+//
+// Since 0.1.15
+func (f *SlimF64) Get(i int32) float64 {
+
+	if f.MaxErr > 0 {
+		var q uint64
+		if f.Quantized.EltWidth == 64 {
+			q = f.Quantized.GetU64(i)
+		} else {
+			q = uint64(f.Quantized.Get(i))
+		}
+		return f.dequantize(q)
+	}
+
+	if f.F32Keys != nil {
+		return float64(keyToF32(f.F32Keys.Get(i)))
+	}
+
+	k := uint64(f.High.Get(i))<<32 | uint64(f.Low.Get(i))
+	return keyToF64(k)
+}
+
+// Slice returns a slice of floats, e.g., similar to foo := nums[start:end].
+// `rst` is used to store returned values, it has to have at least `end-start`
+// elt in it.
+//
+// Since 0.1.15
+func (f *SlimF64) Slice(start int32, end int32, rst []float64) {
+
+	if n := int32(f.Len()); end > n {
+		end = n
+	}
+	if start >= end {
+		return
+	}
+
+	if f.MaxErr > 0 && f.Quantized.EltWidth == 64 {
+		for i := start; i < end; i++ {
+			rst[i-start] = f.dequantize(f.Quantized.GetU64(i))
+		}
+		return
+	}
+
+	buf := make([]uint32, end-start)
+
+	if f.MaxErr > 0 {
+		f.Quantized.Slice(start, end, buf)
+		for i, q := range buf {
+			rst[i] = f.dequantize(uint64(q))
+		}
+		return
+	}
+
+	if f.F32Keys != nil {
+		f.F32Keys.Slice(start, end, buf)
+		for i, k := range buf {
+			rst[i] = float64(keyToF32(k))
+		}
+		return
+	}
+
+	low := make([]uint32, end-start)
+
+	f.High.Slice(start, end, buf)
+	f.Low.Slice(start, end, low)
+	for i, h := range buf {
+		rst[i] = keyToF64(uint64(h)<<32 | uint64(low[i]))
+	}
+}
+
+// GetF32 returns the i-th float of a SlimF64 created by NewF32().
+//
+// Since 0.1.15
+func (f *SlimF64) GetF32(i int32) float32 {
+	if f.MaxErr == 0 && f.F32Keys != nil {
+		return keyToF32(f.F32Keys.Get(i))
+	}
+	return float32(f.Get(i))
+}
+
+// SliceF32 is the same as Slice() but returns float32 of a SlimF64 created by
+// NewF32().
+//
+// Since 0.1.15
+func (f *SlimF64) SliceF32(start int32, end int32, rst []float32) {
+
+	if n := int32(f.Len()); end > n {
+		end = n
+	}
+	if start >= end {
+		return
+	}
+
+	if f.MaxErr == 0 && f.F32Keys != nil {
+		buf := make([]uint32, end-start)
+		f.F32Keys.Slice(start, end, buf)
+		for i, k := range buf {
+			rst[i] = keyToF32(k)
+		}
+		return
+	}
+
+	buf := make([]float64, end-start)
+	f.Slice(start, end, buf)
+	for i, x := range buf {
+		rst[i] = float32(x)
+	}
+}
+
+// step returns the quantization step of a lossy SlimF64.
+func (f *SlimF64) step() float64 {
+	return 2 * f.MaxErr
+}
+
+// quantize finds the quantized value of x within MaxErr.
+// The float arithmetic is the same as dequantize() thus the error is checked
+// exactly.
+func (f *SlimF64) quantize(x float64) (uint64, bool) {
+
+	q := math.Round((x - f.Base) / f.step())
+
+	for _, c := range []float64{q, q - 1, q + 1} {
+		if c < 0 {
+			continue
+		}
+		if math.Abs(f.dequantize(uint64(c))-x) <= f.MaxErr {
+			return uint64(c), true
+		}
+	}
+	return 0, false
+}
+
+// dequantize returns the float of a quantized value.
+// The explicit conversion prevents a fused multiply-add, which may produce a
+// result different from the one checked when building.
+func (f *SlimF64) dequantize(q uint64) float64 {
+	return f.Base + float64(float64(q)*f.step())
+}
+
+// f64ToKey maps a float to an uint64 key, so that the order of keys is the
+// same as the order of floats: a negative float has all bits flipped and a
+// positive float has the sign bit flipped.
+func f64ToKey(x float64) uint64 {
+	b := math.Float64bits(x)
+	if b>>63 == 1 {
+		return ^b
+	}
+	return b | 1<<63
+}
+
+// keyToF64 is the reverse of f64ToKey.
+func keyToF64(k uint64) float64 {
+	if k>>63 == 1 {
+		return math.Float64frombits(k &^ (1 << 63))
+	}
+	return math.Float64frombits(^k)
+}
+
+// f32ToKey is the float32 version of f64ToKey.
+func f32ToKey(x float32) uint32 {
+	b := math.Float32bits(x)
+	if b>>31 == 1 {
+		return ^b
+	}
+	return b | 1<<31
+}
+
+// keyToF32 is the reverse of f32ToKey.
+func keyToF32(k uint32) float32 {
+	if k>>31 == 1 {
+		return math.Float32frombits(k &^ (1 << 31))
+	}
+	return math.Float32frombits(^k)
+}
@@ -0,0 +1,263 @@
+package slimarray
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/stretchr/testify/require"
+)
+
+// sensorSeries returns a series of floats with a trend and noise.
+func sensorSeries(n int, seed int64) []float64 {
+	rnd := rand.New(rand.NewSource(seed))
+
+	nums := make([]float64, n)
+	for i := range nums {
+		x := float64(i)
+		nums[i] = 20 + 5*math.Sin(x/500) + x/1000 + rnd.NormFloat64()*0.05
+	}
+	return nums
+}
+
+func TestNewF64_lossless(t *testing.T) {
+
+	ta := require.New(t)
+
+	cases := [][]float64{
+		{},
+		{0},
+		{1, 2, 3},
+		{math.Copysign(0, -1), 0, -1.5, 1.5, math.Inf(1), math.Inf(-1), math.NaN(),
+			math.MaxFloat64, -math.MaxFloat64, math.SmallestNonzeroFloat64},
+		sensorSeries(3000, 0),
+	}
+
+	for i, nums := range cases {
+		f, err := NewF64(nums, nil)
+		ta.NoError(err)
+		ta.Equal(float64(0), f.MaxErr)
+		ta.Equal(len(nums), f.Len())
+
+		rst := make([]float64, len(nums))
+		f.Slice(0, int32(len(nums)), rst)
+
+		for j, x := range nums {
+			ta.Equal(math.Float64bits(x), math.Float64bits(f.Get(int32(j))), "%d-th case: %d", i+1, j)
+			ta.Equal(math.Float64bits(x), math.Float64bits(rst[j]), "%d-th case: %d", i+1, j)
+		}
+	}
+}
+
+func TestNewF64_lossy(t *testing.T) {
+
+	ta := require.New(t)
+
+	nums := sensorSeries(3000, 0)
+	nums = append(nums, -1e6, 1e6, 0, -0.1)
+
+	for _, maxErr := range []float64{1e-6, 0.001, 0.1, 3, 1e7} {
+
+		f, err := NewF64(nums, &F64Opt{MaxErr: maxErr})
+		ta.NoError(err)
+		ta.Equal(maxErr, f.MaxErr)
+		ta.Equal(len(nums), f.Len())
+
+		testF64Lossy(ta, f, nums, maxErr)
+	}
+}
+
+func TestNewF64_lossy_quantized64(t *testing.T) {
+
+	ta := require.New(t)
+
+	// (max - min) / (2 * maxErr) exceeds max value of uint32
+	nums := []float64{0, 1e6, -1e6, 12345.678901, 0.5}
+
+	f, err := NewF64(nums, &F64Opt{MaxErr: 1e-6})
+	ta.NoError(err)
+	ta.Equal(int32(64), f.Quantized.EltWidth)
+
+	testF64Lossy(ta, f, nums, 1e-6)
+}
+
+func TestNewF64_lossy_error(t *testing.T) {
+
+	ta := require.New(t)
+
+	_, err := NewF64([]float64{1, math.NaN()}, &F64Opt{MaxErr: 0.1})
+	ta.Equal(NotFinite, err)
+
+	_, err = NewF64([]float64{1, math.Inf(-1)}, &F64Opt{MaxErr: 0.1})
+	ta.Equal(NotFinite, err)
+
+	_, err = NewF64([]float64{0, 1e10}, &F64Opt{MaxErr: 1e-10})
+	ta.Equal(MaxErrTooSmall, err)
+
+	// Dequantized values around 1e6 have a precision of 1.16e-10, there are
+	// gaps greater than 2*maxErr between them.
+	_, err = NewF64([]float64{-1e6, 1e6, 20.89344110105163}, &F64Opt{MaxErr: 1e-9})
+	ta.Equal(MaxErrTooSmall, err)
+}
+
+func TestNewF64_compression(t *testing.T) {
+
+	ta := require.New(t)
+
+	nums := sensorSeries(100*1000, 0)
+
+	lossless, err := NewF64(nums, nil)
+	ta.NoError(err)
+
+	highBits := float64(spanBits(lossless.High)) / float64(len(nums))
+	lowBits := float64(spanBits(lossless.Low)) / float64(len(nums))
+	t.Logf("lossless: high: %.3f bits/elt low: %.3f bits/elt", highBits, lowBits)
+
+	ta.Less(highBits, float64(20))
+
+	for _, maxErr := range []float64{0.001, 0.01, 0.1} {
+		f, err := NewF64(nums, &F64Opt{MaxErr: maxErr})
+		ta.NoError(err)
+
+		bits := float64(spanBits(f.Quantized)) / float64(len(nums))
+		t.Logf("maxErr: %.3f: %.3f bits/elt", maxErr, bits)
+
+		ta.Less(bits, highBits+lowBits)
+	}
+}
+
+func TestNewF32(t *testing.T) {
+
+	ta := require.New(t)
+
+	nums := []float32{float32(math.Copysign(0, -1)), 0, -1.5, 1.5,
+		float32(math.Inf(1)), float32(math.NaN()), math.MaxFloat32, math.SmallestNonzeroFloat32}
+	for _, x := range sensorSeries(3000, 0) {
+		nums = append(nums, float32(x))
+	}
+
+	f, err := NewF32(nums, nil)
+	ta.NoError(err)
+	ta.Equal(len(nums), f.Len())
+
+	rst := make([]float32, len(nums))
+	f.SliceF32(0, int32(len(nums)), rst)
+
+	for i, x := range nums {
+		ta.Equal(math.Float32bits(x), math.Float32bits(f.GetF32(int32(i))), "i: %d", i)
+		ta.Equal(math.Float32bits(x), math.Float32bits(rst[i]), "i: %d", i)
+	}
+
+	for i, x := range nums {
+		ta.Equal(math.Float64bits(float64(x)), math.Float64bits(f.Get(int32(i))), "i: %d", i)
+	}
+
+	// A uint32 key costs less than storing the float32 as a float64.
+	f64s := make([]float64, len(nums))
+	for i, x := range nums {
+		f64s[i] = float64(x)
+	}
+	f64, err := NewF64(f64s, nil)
+	ta.NoError(err)
+	ta.Less(spanBits(f.F32Keys), spanBits(f64.High)+spanBits(f64.Low))
+
+	bytes, err := proto.Marshal(f)
+	ta.NoError(err)
+	b := &SlimF64{}
+	ta.NoError(proto.Unmarshal(bytes, b))
+	for i, x := range nums {
+		ta.Equal(math.Float32bits(x), math.Float32bits(b.GetF32(int32(i))), "i: %d", i)
+	}
+
+	// lossy
+	nums = nums[8:]
+	maxErr := 0.01
+	f, err = NewF32(nums, &F64Opt{MaxErr: maxErr})
+	ta.NoError(err)
+
+	f.SliceF32(0, int32(len(nums)), rst)
+	for i, x := range nums {
+		got := f.GetF32(int32(i))
+		ta.Equal(got, rst[i], "Slice, i: %d", i)
+
+		// plus the rounding error of float32
+		tolerance := maxErr + math.Abs(float64(x))*(1.0/(1<<23))
+		ta.LessOrEqual(math.Abs(float64(got)-float64(x)), tolerance, "i: %d", i)
+	}
+}
+
+func TestNewF64_marshalUnmarshal(t *testing.T) {
+
+	ta := require.New(t)
+
+	nums := sensorSeries(2000, 1)
+
+	for _, opt := range []*F64Opt{nil, {MaxErr: 0.01}} {
+		f, err := NewF64(nums, opt)
+		ta.NoError(err)
+
+		bytes, err := proto.Marshal(f)
+		ta.NoError(err)
+
+		b := &SlimF64{}
+		err = proto.Unmarshal(bytes, b)
+		ta.NoError(err)
+
+		for i := range nums {
+			ta.Equal(f.Get(int32(i)), b.Get(int32(i)))
+		}
+	}
+}
+
+func TestF64ToKey(t *testing.T) {
+
+	ta := require.New(t)
+
+	nums := []float64{
+		math.Inf(-1), -math.MaxFloat64, -1e10, -1.5, -math.SmallestNonzeroFloat64,
+		math.Copysign(0, -1), 0, math.SmallestNonzeroFloat64, 1, 1e300, math.Inf(1),
+	}
+
+	for i, x := range nums {
+		ta.Equal(math.Float64bits(x), math.Float64bits(keyToF64(f64ToKey(x))))
+		if i > 0 {
+			ta.Less(f64ToKey(nums[i-1]), f64ToKey(x), "%v < %v", nums[i-1], x)
+		}
+	}
+}
+
+func testF64Lossy(ta *require.Assertions, f *SlimF64, nums []float64, maxErr float64) {
+
+	rst := make([]float64, len(nums))
+	f.Slice(0, int32(len(nums)), rst)
+
+	for i, x := range nums {
+		got := f.Get(int32(i))
+		ta.LessOrEqual(math.Abs(got-x), maxErr, "i: %d, maxErr: %v", i, maxErr)
+		ta.Equal(got, rst[i], "Slice, i: %d", i)
+	}
+}
+
+func BenchmarkSlimF64_Get(b *testing.B) {
+
+	nums := sensorSeries(1024*64, 0)
+
+	for _, opt := range []*F64Opt{nil, {MaxErr: 0.01}} {
+		f, _ := NewF64(nums, opt)
+		name := "lossless"
+		if opt != nil {
+			name = "lossy"
+		}
+
+		b.Run(name, func(b *testing.B) {
+			var s float64
+			for i := 0; i < b.N; i++ {
+				s += f.Get(int32(i & 0xffff))
+			}
+			OutputF64 = s
+		})
+	}
+}
+
+var OutputF64 float64
@@ -0,0 +1,180 @@
+package slimarray
+
+import (
+	"errors"
+	"sort"
+)
+
+var (
+	// VertexOutOfRange is returned if a vertex of an edge is not less than the
+	// number of vertices.
+	VertexOutOfRange = errors.New("vertex out of range")
+
+	// NegativeVertexCount is returned if the number of vertices is less than
+	// 0.
+	NegativeVertexCount = errors.New("vertex count is negative")
+)
+
+// Edge is a directed edge from vertex From to vertex To.
+//
+// Since 0.1.15
+type Edge struct {
+	From, To uint32
+}
+
+// NewGraph creates a SlimGraph of n vertices from directed edges.
+// Edges need not to be sorted. Duplicated edges are stored only once.
+//
+// It returns NegativeVertexCount if n < 0, VertexOutOfRange if a vertex of an
+// edge is not less than n, or TooManyRows if there are more than 2^31-1
+// vertices or edges.
+//
+// Since 0.1.15
+func NewGraph(n int, edges []Edge) (*SlimGraph, error) {
+
+	if n < 0 {
+		return nil, NegativeVertexCount
+	}
+
+	if int64(n) > 0x7fffffff-1 || int64(len(edges)) > 0x7fffffff {
+		return nil, TooManyRows
+	}
+
+	// counting sort edges by From
+
+	offsets := make([]uint32, n+1)
+	for _, e := range edges {
+		if int64(e.From) >= int64(n) || int64(e.To) >= int64(n) {
+			return nil, VertexOutOfRange
+		}
+		offsets[e.From+1]++
+	}
+
+	for v := 0; v < n; v++ {
+		offsets[v+1] += offsets[v]
+	}
+
+	neighbors := make([]uint32, len(edges))
+	pos := append([]uint32{}, offsets[:n]...)
+	for _, e := range edges {
+		neighbors[pos[e.From]] = e.To
+		pos[e.From]++
+	}
+
+	// sort and remove duplicates in every row
+
+	m := uint32(0)
+	for v := 0; v < n; v++ {
+		row := neighbors[offsets[v]:offsets[v+1]]
+		sort.Slice(row, func(i, j int) bool { return row[i] < row[j] })
+
+		offsets[v] = m
+		for i, u := range row {
+			if i > 0 && u == row[i-1] {
+				continue
+			}
+			neighbors[m] = u
+			m++
+		}
+	}
+	offsets[n] = m
+
+	g := &SlimGraph{
+		Offsets: NewU32(offsets),
+		Targets: NewU32(neighbors[:m]),
+	}
+
+	return g, nil
+}
+
+// NumVertices returns the number of vertices.
+//
+// Since 0.1.15
+func (g *SlimGraph) NumVertices() int {
+	return g.Offsets.Len() - 1
+}
+
+// NumEdges returns the number of edges.
+//
+// Since 0.1.15
+func (g *SlimGraph) NumEdges() int {
+	return g.Targets.Len()
+}
+
+// Degree returns the number of out-going edges of vertex v.
+// It returns 0 if v is not less than NumVertices().
+//
+// Since 0.1.15
+func (g *SlimGraph) Degree(v uint32) int {
+
+	if int64(v) >= int64(g.NumVertices()) {
+		return 0
+	}
+
+	s, e := g.Offsets.Get2(int32(v))
+	return int(e - s)
+}
+
+// Neighbors returns the sorted vertices that v has an edge to.
+// It returns nil if v is not less than NumVertices().
+//
+// Since 0.1.15
+func (g *SlimGraph) Neighbors(v uint32) []uint32 {
+
+	if int64(v) >= int64(g.NumVertices()) {
+		return nil
+	}
+
+	s, e := g.Offsets.Get2(int32(v))
+	rst := make([]uint32, e-s)
+	g.Targets.Slice(int32(s), int32(e), rst)
+	return rst
+}
+
+// HasEdge returns true if there is an edge from u to v.
+// It is a binary search in the neighbors of u.
+//
+// Since 0.1.15
+func (g *SlimGraph) HasEdge(u, v uint32) bool {
+
+	if int64(u) >= int64(g.NumVertices()) {
+		return false
+	}
+
+	s, e := g.Offsets.Get2(int32(u))
+
+	i := sort.Search(int(e-s), func(i int) bool {
+		return g.Targets.Get(int32(s)+int32(i)) >= v
+	})
+
+	return i < int(e-s) && g.Targets.Get(int32(s)+int32(i)) == v
+}
+
+// Edges calls fn for every edge in ascending order of From then To, until fn
+// returns false.
+//
+// Since 0.1.15
+func (g *SlimGraph) Edges(fn func(from, to uint32) bool) {
+
+	n := g.NumVertices()
+	var buf []uint32
+
+	for v := 0; v < n; v++ {
+		s, e := g.Offsets.Get2(int32(v))
+		if s == e {
+			continue
+		}
+
+		if cap(buf) < int(e-s) {
+			buf = make([]uint32, e-s)
+		}
+		row := buf[:e-s]
+		g.Targets.Slice(int32(s), int32(e), row)
+
+		for _, u := range row {
+			if !fn(uint32(v), u) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,242 @@
+package slimarray
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewGraph(t *testing.T) {
+
+	ta := require.New(t)
+
+	g, err := NewGraph(5, []Edge{
+		{0, 1}, {0, 3}, {0, 2},
+		{2, 0},
+		{3, 3}, {3, 1}, {3, 3},
+	})
+	ta.NoError(err)
+
+	ta.Equal(5, g.NumVertices())
+	ta.Equal(6, g.NumEdges())
+
+	wants := [][]uint32{
+		{1, 2, 3},
+		{},
+		{0},
+		{1, 3},
+		{},
+	}
+
+	for v, want := range wants {
+		ta.Equal(want, g.Neighbors(uint32(v)), "v: %d", v)
+		ta.Equal(len(want), g.Degree(uint32(v)), "v: %d", v)
+	}
+
+	ta.True(g.HasEdge(0, 2))
+	ta.True(g.HasEdge(3, 3))
+	ta.False(g.HasEdge(2, 3))
+	ta.False(g.HasEdge(1, 0))
+	ta.False(g.HasEdge(4, 0))
+	ta.False(g.HasEdge(5, 0))
+	ta.False(g.HasEdge(0, 100))
+
+	// out of range
+	for _, v := range []uint32{5, 6, 0x7fffffff, 0xffffffff} {
+		ta.Equal(0, g.Degree(v), "v: %d", v)
+		ta.Nil(g.Neighbors(v), "v: %d", v)
+	}
+
+	var got []Edge
+	g.Edges(func(from, to uint32) bool {
+		got = append(got, Edge{from, to})
+		return true
+	})
+	ta.Equal([]Edge{{0, 1}, {0, 2}, {0, 3}, {2, 0}, {3, 1}, {3, 3}}, got)
+
+	// stop iteration
+	got = got[:0]
+	g.Edges(func(from, to uint32) bool {
+		got = append(got, Edge{from, to})
+		return len(got) < 4
+	})
+	ta.Equal([]Edge{{0, 1}, {0, 2}, {0, 3}, {2, 0}}, got)
+}
+
+func TestNewGraph_empty(t *testing.T) {
+
+	ta := require.New(t)
+
+	g, err := NewGraph(0, nil)
+	ta.NoError(err)
+	ta.Equal(0, g.NumVertices())
+	ta.Equal(0, g.NumEdges())
+	ta.False(g.HasEdge(0, 0))
+	ta.Equal(0, g.Degree(0))
+	ta.Nil(g.Neighbors(0))
+
+	g, err = NewGraph(3, nil)
+	ta.NoError(err)
+	ta.Equal(3, g.NumVertices())
+	ta.Equal([]uint32{}, g.Neighbors(2))
+
+	g.Edges(func(from, to uint32) bool {
+		panic("no edge")
+	})
+}
+
+func TestNewGraph_error(t *testing.T) {
+
+	ta := require.New(t)
+
+	_, err := NewGraph(3, []Edge{{0, 3}})
+	ta.Equal(VertexOutOfRange, err)
+
+	_, err = NewGraph(3, []Edge{{3, 0}})
+	ta.Equal(VertexOutOfRange, err)
+
+	_, err = NewGraph(-1, nil)
+	ta.Equal(NegativeVertexCount, err)
+}
+
+func TestSlimGraph_random(t *testing.T) {
+
+	ta := require.New(t)
+
+	n := 5000
+	edges, adj := randGraph(n, 50000, 0)
+
+	g, err := NewGraph(n, edges)
+	ta.NoError(err)
+
+	m := 0
+	for v := 0; v < n; v++ {
+		want := adj[v]
+		ta.Equal(want, g.Neighbors(uint32(v)))
+		ta.Equal(len(want), g.Degree(uint32(v)))
+		m += len(want)
+
+		for k, u := range want {
+			ta.True(g.HasEdge(uint32(v), u))
+
+			// want is sorted: u-1 is a neighbor only if it is the previous one
+			if u > 0 && (k == 0 || want[k-1] != u-1) {
+				ta.False(g.HasEdge(uint32(v), u-1))
+			}
+		}
+	}
+	ta.Equal(m, g.NumEdges())
+
+	cnt := 0
+	g.Edges(func(from, to uint32) bool {
+		ta.Equal(adj[from][0], to)
+		adj[from] = adj[from][1:]
+		cnt++
+		return true
+	})
+	ta.Equal(m, cnt)
+
+	plain := 4 * (n + 1 + m)
+	t.Logf("vertices: %d edges: %d, SlimGraph: %d bytes, plain CSR: %d bytes",
+		n, m, proto.Size(g), plain)
+	ta.Less(proto.Size(g), plain)
+}
+
+func TestSlimGraph_marshalUnmarshal(t *testing.T) {
+
+	ta := require.New(t)
+
+	edges, adj := randGraph(100, 1000, 1)
+
+	g, err := NewGraph(100, edges)
+	ta.NoError(err)
+
+	bytes, err := proto.Marshal(g)
+	ta.NoError(err)
+
+	b := &SlimGraph{}
+	err = proto.Unmarshal(bytes, b)
+	ta.NoError(err)
+
+	for v, want := range adj {
+		ta.Equal(want, b.Neighbors(uint32(v)))
+	}
+}
+
+// randGraph returns random edges with duplicates, and the sorted adjacency
+// list.
+// A vertex has edges to nearby vertices more likely, as in a real world graph.
+func randGraph(n, m int, seed int64) ([]Edge, [][]uint32) {
+
+	rnd := rand.New(rand.NewSource(seed))
+
+	edges := make([]Edge, m)
+	for i := range edges {
+		from := rnd.Intn(n)
+		to := rnd.Intn(n)
+		if rnd.Intn(4) > 0 {
+			to = (from + rnd.Intn(100)) % n
+		}
+		edges[i] = Edge{uint32(from), uint32(to)}
+	}
+
+	set := make([]map[uint32]bool, n)
+	for _, e := range edges {
+		if set[e.From] == nil {
+			set[e.From] = map[uint32]bool{}
+		}
+		set[e.From][e.To] = true
+	}
+
+	adj := make([][]uint32, n)
+	for v := range adj {
+		adj[v] = []uint32{}
+		for u := range set[v] {
+			adj[v] = append(adj[v], u)
+		}
+		row := adj[v]
+		sort.Slice(row, func(i, j int) bool { return row[i] < row[j] })
+	}
+
+	return edges, adj
+}
+
+func BenchmarkSlimGraph(b *testing.B) {
+
+	n := 1024 * 64
+	edges, _ := randGraph(n, n*16, 0)
+	g, _ := NewGraph(n, edges)
+
+	b.Run("Neighbors", func(b *testing.B) {
+		s := 0
+		for i := 0; i < b.N; i++ {
+			s += len(g.Neighbors(uint32(i % n)))
+		}
+		Output = s
+	})
+
+	b.Run("HasEdge", func(b *testing.B) {
+		s := 0
+		for i := 0; i < b.N; i++ {
+			v := i % n
+			if g.HasEdge(uint32(v), uint32(v+7)) {
+				s++
+			}
+		}
+		Output = s
+	})
+
+	b.Run("Edges", func(b *testing.B) {
+		s := 0
+		for i := 0; i < b.N; i++ {
+			g.Edges(func(from, to uint32) bool {
+				s++
+				return true
+			})
+		}
+		Output = s
+	})
+}
@@ -0,0 +1,169 @@
+package slimarray
+
+import (
+	"bytes"
+	"errors"
+	"sort"
+
+	"github.com/openacid/low/size"
+)
+
+var (
+	KeyValueCountMismatch = errors.New("count of keys and values differ")
+	DuplicatedKey         = errors.New("duplicated key")
+)
+
+// NewMap creates a SlimMap from keys and the corresponding values.
+// keys does not need to be sorted, but must not have duplicated key.
+//
+// Since 0.1.15
+func NewMap(keys, values [][]byte) (*SlimMap, error) {
+
+	if len(keys) != len(values) {
+		return nil, KeyValueCountMismatch
+	}
+
+	idx := make([]int, len(keys))
+	for i := range idx {
+		idx[i] = i
+	}
+
+	sort.SliceStable(idx, func(i, j int) bool {
+		return bytes.Compare(keys[idx[i]], keys[idx[j]]) < 0
+	})
+
+	ks := make([][]byte, len(keys))
+	vs := make([][]byte, len(keys))
+	for i, j := range idx {
+		ks[i] = keys[j]
+		vs[i] = values[j]
+
+		if i > 0 && bytes.Equal(ks[i-1], ks[i]) {
+			return nil, DuplicatedKey
+		}
+	}
+
+	kb, err := NewBytes(ks)
+	if err != nil {
+		return nil, err
+	}
+
+	vb, err := NewBytes(vs)
+	if err != nil {
+		return nil, err
+	}
+
+	m := &SlimMap{
+		Keys:   kb,
+		Values: vb,
+	}
+	return m, nil
+}
+
+// Len returns the number of keys.
+//
+// Since 0.1.15
+func (m *SlimMap) Len() int {
+	return m.Keys.Len()
+}
+
+// Key returns the i-th key.
+//
+// Since 0.1.15
+func (m *SlimMap) Key(i int32) []byte {
+	return m.Keys.Get(i)
+}
+
+// Value returns the i-th value.
+//
+// Since 0.1.15
+func (m *SlimMap) Value(i int32) []byte {
+	return m.Values.Get(i)
+}
+
+// Search returns the index of the first key that is greater than or equal to
+// key. It returns Len() if there is no such key.
+//
+// Since 0.1.15
+func (m *SlimMap) Search(key []byte) int32 {
+	n := int32(m.Len())
+	return int32(sort.Search(int(n), func(i int) bool {
+		return bytes.Compare(m.Keys.Get(int32(i)), key) >= 0
+	}))
+}
+
+// Get returns the value of key and true.
+// It returns nil and false if key is not found.
+//
+// Since 0.1.15
+func (m *SlimMap) Get(key []byte) ([]byte, bool) {
+	i := m.Search(key)
+	if int(i) < m.Len() && bytes.Equal(m.Keys.Get(i), key) {
+		return m.Values.Get(i), true
+	}
+	return nil, false
+}
+
+// Range calls fn for every key in range [start, end) in ascending order, until
+// fn returns false.
+// A nil end means there is no upper bound.
+//
+// Since 0.1.15
+func (m *SlimMap) Range(start, end []byte, fn func(key, value []byte) bool) {
+	n := int32(m.Len())
+	for i := m.Search(start); i < n; i++ {
+		k := m.Keys.Get(i)
+		if end != nil && bytes.Compare(k, end) >= 0 {
+			return
+		}
+		if !fn(k, m.Values.Get(i)) {
+			return
+		}
+	}
+}
+
+// Prefix calls fn for every key that starts with prefix in ascending order,
+// until fn returns false.
+//
+// Since 0.1.15
+func (m *SlimMap) Prefix(prefix []byte, fn func(key, value []byte) bool) {
+	n := int32(m.Len())
+	for i := m.Search(prefix); i < n; i++ {
+		k := m.Keys.Get(i)
+		if !bytes.HasPrefix(k, prefix) {
+			return
+		}
+		if !fn(k, m.Values.Get(i)) {
+			return
+		}
+	}
+}
+
+// Stat returns a map describing memory usage.
+// Sizes are int64 because a map may hold more than 2 GB.
+//
+//    n          :3      // total key count
+//    mem_keys   :302    // memory cost of keys and key positions
+//    mem_values :305    // memory cost of values and value positions
+//    mem_total  :651    // total memory cost
+//    bits/elt   :1736   // average memory cost per key-value pair
+//
+// Since 0.1.15
+func (m *SlimMap) Stat() map[string]int64 {
+	totalmem := int64(size.Of(m))
+
+	n := int64(m.Len())
+	if n == 0 {
+		n = 1
+	}
+
+	st := map[string]int64{
+		"n":          int64(m.Len()),
+		"mem_keys":   int64(size.Of(m.Keys)),
+		"mem_values": int64(size.Of(m.Values)),
+		"mem_total":  totalmem,
+		"bits/elt":   totalmem * 8 / n,
+	}
+
+	return st
+}
@@ -0,0 +1,254 @@
+package slimarray
+
+import (
+	"fmt"
+	"sort"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/openacid/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewMap(t *testing.T) {
+
+	ta := require.New(t)
+
+	_, err := NewMap([][]byte{[]byte("a")}, nil)
+	ta.Equal(KeyValueCountMismatch, err)
+
+	_, err = NewMap(
+		[][]byte{[]byte("b"), []byte("a"), []byte("b")},
+		[][]byte{[]byte("1"), []byte("2"), []byte("3")},
+	)
+	ta.Equal(DuplicatedKey, err)
+
+	m, err := NewMap(nil, nil)
+	ta.NoError(err)
+	ta.Equal(0, m.Len())
+	_, found := m.Get([]byte("a"))
+	ta.False(found)
+}
+
+func TestSlimMap_Get(t *testing.T) {
+
+	ta := require.New(t)
+
+	keys := [][]byte{
+		[]byte("foo"), []byte("bar"), []byte(""), []byte("hello"), []byte("xp"),
+	}
+	values := [][]byte{
+		[]byte("1"), []byte("2"), []byte("3"), []byte("4"), []byte("5"),
+	}
+
+	m, err := NewMap(keys, values)
+	ta.NoError(err)
+	ta.Equal(5, m.Len())
+
+	for i, k := range keys {
+		v, found := m.Get(k)
+		ta.True(found)
+		ta.Equal(values[i], v)
+	}
+
+	for _, k := range []string{"a", "bar0", "fo", "z"} {
+		v, found := m.Get([]byte(k))
+		ta.False(found)
+		ta.Nil(v)
+	}
+
+	ta.Equal("", string(m.Key(0)))
+	ta.Equal("3", string(m.Value(0)))
+	ta.Equal("xp", string(m.Key(4)))
+
+	ta.Equal(int32(0), m.Search([]byte("")))
+	ta.Equal(int32(2), m.Search([]byte("c")))
+	ta.Equal(int32(5), m.Search([]byte("z")))
+}
+
+func TestSlimMap_Get_big(t *testing.T) {
+
+	ta := require.New(t)
+
+	n := 1024 * 10
+	keys := testutil.RandBytesSlice(n, 5, 20)
+	keys = uniq(keys)
+	values := testutil.RandBytesSlice(len(keys), 0, 10)
+
+	m, err := NewMap(keys, values)
+	ta.NoError(err)
+
+	for i, k := range keys {
+		v, found := m.Get(k)
+		ta.True(found)
+		ta.Equal(values[i], v)
+	}
+}
+
+func TestSlimMap_Range(t *testing.T) {
+
+	ta := require.New(t)
+
+	m := newTestMap(ta, "a", "ab", "abc", "b", "ba", "c")
+
+	cases := []struct {
+		start, end string
+		nilEnd     bool
+		want       []string
+	}{
+		{"", "", true, []string{"a", "ab", "abc", "b", "ba", "c"}},
+		{"", "", false, []string{}},
+		{"ab", "b", false, []string{"ab", "abc"}},
+		{"aa", "ba", false, []string{"ab", "abc", "b"}},
+		{"b", "", true, []string{"b", "ba", "c"}},
+		{"d", "", true, []string{}},
+	}
+
+	for i, c := range cases {
+		var end []byte
+		if !c.nilEnd {
+			end = []byte(c.end)
+		}
+
+		got := []string{}
+		m.Range([]byte(c.start), end, func(k, v []byte) bool {
+			ta.Equal("v"+string(k), string(v))
+			got = append(got, string(k))
+			return true
+		})
+		ta.Equal(c.want, got, "%d-th: case: %+v", i+1, c)
+	}
+
+	// stop
+	got := []string{}
+	m.Range(nil, nil, func(k, v []byte) bool {
+		got = append(got, string(k))
+		return len(got) < 2
+	})
+	ta.Equal([]string{"a", "ab"}, got)
+}
+
+func TestSlimMap_Prefix(t *testing.T) {
+
+	ta := require.New(t)
+
+	m := newTestMap(ta, "a", "ab", "abc", "b", "ba", "c")
+
+	cases := []struct {
+		prefix string
+		want   []string
+	}{
+		{"", []string{"a", "ab", "abc", "b", "ba", "c"}},
+		{"a", []string{"a", "ab", "abc"}},
+		{"ab", []string{"ab", "abc"}},
+		{"abcd", []string{}},
+		{"b", []string{"b", "ba"}},
+		{"bb", []string{}},
+		{"d", []string{}},
+	}
+
+	for i, c := range cases {
+		got := []string{}
+		m.Prefix([]byte(c.prefix), func(k, v []byte) bool {
+			got = append(got, string(k))
+			return true
+		})
+		ta.Equal(c.want, got, "%d-th: case: %+v", i+1, c)
+	}
+
+	got := []string{}
+	m.Prefix([]byte("a"), func(k, v []byte) bool {
+		got = append(got, string(k))
+		return false
+	})
+	ta.Equal([]string{"a"}, got)
+}
+
+func TestSlimMap_Stat(t *testing.T) {
+
+	ta := require.New(t)
+
+	m := newTestMap(ta, "a", "ab", "abc")
+	st := m.Stat()
+	fmt.Println(st)
+
+	ta.Equal(int64(3), st["n"])
+	ta.True(st["mem_keys"] > 0)
+	ta.True(st["mem_values"] > 0)
+	ta.True(st["mem_total"] > st["mem_keys"]+st["mem_values"])
+	ta.Equal(st["mem_total"]*8/3, st["bits/elt"])
+}
+
+func TestSlimMap_marshalUnmarshal(t *testing.T) {
+
+	ta := require.New(t)
+
+	m := newTestMap(ta, "a", "ab", "abc", "b", "ba", "c")
+
+	bytes, err := proto.Marshal(m)
+	ta.NoError(err)
+
+	b := &SlimMap{}
+	err = proto.Unmarshal(bytes, b)
+	ta.NoError(err)
+
+	for _, k := range []string{"a", "ab", "abc", "b", "ba", "c"} {
+		v, found := b.Get([]byte(k))
+		ta.True(found)
+		ta.Equal("v"+k, string(v))
+	}
+}
+
+func newTestMap(ta *require.Assertions, keys ...string) *SlimMap {
+	ks := [][]byte{}
+	vs := [][]byte{}
+	for _, k := range keys {
+		ks = append(ks, []byte(k))
+		vs = append(vs, []byte("v"+k))
+	}
+
+	m, err := NewMap(ks, vs)
+	ta.NoError(err)
+	return m
+}
+
+func uniq(keys [][]byte) [][]byte {
+	set := map[string]bool{}
+	for _, k := range keys {
+		set[string(k)] = true
+	}
+
+	rst := [][]byte{}
+	for k := range set {
+		rst = append(rst, []byte(k))
+	}
+	sort.Slice(rst, func(i, j int) bool { return string(rst[i]) < string(rst[j]) })
+	return rst
+}
+
+var OutputSlimMap int
+
+func BenchmarkSlimMap_Get(b *testing.B) {
+
+	for _, n := range []int{1024, 1024 * 1024} {
+		b.Run(fmt.Sprintf("n=%d", n), func(b *testing.B) {
+
+			keys := uniq(testutil.RandBytesSlice(n, 10, 20))
+			values := testutil.RandBytesSlice(len(keys), 5, 10)
+
+			m, _ := NewMap(keys, values)
+
+			l := len(keys)
+
+			b.ResetTimer()
+
+			s := 0
+			for i := 0; i < b.N; i++ {
+				v, _ := m.Get(keys[i%l])
+				s += len(v)
+			}
+
+			OutputSlimMap = s
+		})
+	}
+}
@@ -0,0 +1,438 @@
+package slimarray
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+)
+
+var (
+	// InvalidSchema is returned if a table schema has no column, has a column
+	// with empty or duplicated name, or has a column of unknown type.
+	InvalidSchema = errors.New("invalid table schema")
+
+	// UnknownColumn is returned if a column name is not in the schema.
+	UnknownColumn = errors.New("unknown column")
+
+	// RowMismatch is returned if the number or the types of fields of a row do
+	// not match the schema.
+	RowMismatch = errors.New("row does not match schema")
+
+	// ValueRangeTooLarge is returned if the max value of an int64 column minus
+	// the min value is not less than 2^63, e.g., a column has both
+	// math.MinInt64 and math.MaxInt64.
+	ValueRangeTooLarge = errors.New("range of int64 column exceeds 2^63-1")
+)
+
+// ColumnType is the type of values in a column of SlimTable.
+//
+// Since 0.1.15
+type ColumnType int32
+
+const (
+	// ColumnU32 is a column of uint32.
+	ColumnU32 ColumnType = iota + 1
+
+	// ColumnI64 is a column of int64.
+	ColumnI64
+
+	// ColumnBytes is a column of []byte.
+	// A string field is also accepted when adding a row.
+	ColumnBytes
+)
+
+// ColumnDef defines the name and type of a column.
+//
+// Since 0.1.15
+type ColumnDef struct {
+	Name string
+	Type ColumnType
+}
+
+// TableOpt specifies how to build a SlimTable.
+//
+// Since 0.1.15
+type TableOpt struct {
+	// Opt specifies how to build SlimArray of numeric columns.
+	Opt
+
+	// SortBy is the name of the column to sort rows by.
+	// Rows with equal values keep the order they are added.
+	// By default rows are not sorted.
+	SortBy string
+}
+
+// TableBuilder collects rows to build a SlimTable.
+//
+// Since 0.1.15
+type TableBuilder struct {
+	schema []ColumnDef
+	n      int64
+
+	u32s  [][]uint32
+	i64s  [][]int64
+	bytes [][][]byte
+}
+
+// NewTableBuilder creates a TableBuilder with the schema.
+// It returns InvalidSchema if schema is empty, has a column of empty or
+// duplicated name, or has a column of unknown type.
+//
+// Since 0.1.15
+func NewTableBuilder(schema []ColumnDef) (*TableBuilder, error) {
+
+	if len(schema) == 0 {
+		return nil, InvalidSchema
+	}
+
+	names := map[string]bool{}
+	for _, c := range schema {
+		if c.Name == "" || names[c.Name] {
+			return nil, InvalidSchema
+		}
+		if c.Type < ColumnU32 || c.Type > ColumnBytes {
+			return nil, InvalidSchema
+		}
+		names[c.Name] = true
+	}
+
+	n := len(schema)
+	b := &TableBuilder{
+		schema: append([]ColumnDef{}, schema...),
+		u32s:   make([][]uint32, n),
+		i64s:   make([][]int64, n),
+		bytes:  make([][][]byte, n),
+	}
+	return b, nil
+}
+
+// Add adds a row. Fields must be in schema order, and the type of a field must
+// be uint32 for a ColumnU32, int64 for a ColumnI64, or []byte or string for a
+// ColumnBytes. A []byte field is not copied.
+//
+// It returns RowMismatch if fields do not match the schema, or TooManyRows if
+// there are already 2^31-1 rows.
+//
+// Since 0.1.15
+func (b *TableBuilder) Add(fields ...interface{}) error {
+
+	if len(fields) != len(b.schema) {
+		return RowMismatch
+	}
+
+	if b.n >= 0x7fffffff {
+		return TooManyRows
+	}
+
+	// check all fields before adding any, to keep columns of equal length.
+	for j, f := range fields {
+		ok := false
+		switch f.(type) {
+		case uint32:
+			ok = b.schema[j].Type == ColumnU32
+		case int64:
+			ok = b.schema[j].Type == ColumnI64
+		case []byte, string:
+			ok = b.schema[j].Type == ColumnBytes
+		}
+		if !ok {
+			return RowMismatch
+		}
+	}
+
+	for j, f := range fields {
+		switch v := f.(type) {
+		case uint32:
+			b.u32s[j] = append(b.u32s[j], v)
+		case int64:
+			b.i64s[j] = append(b.i64s[j], v)
+		case []byte:
+			b.bytes[j] = append(b.bytes[j], v)
+		case string:
+			b.bytes[j] = append(b.bytes[j], []byte(v))
+		}
+	}
+	b.n++
+
+	return nil
+}
+
+// Build creates a SlimTable from all added rows.
+// opt could be nil to use default options.
+//
+// It returns UnknownColumn if opt.SortBy is not a column in the schema.
+// It returns ValueRangeTooLarge if the values of an int64 column span 2^63 or
+// more.
+//
+// Since 0.1.15
+func (b *TableBuilder) Build(opt *TableOpt) (*SlimTable, error) {
+
+	if opt == nil {
+		opt = &TableOpt{}
+	}
+
+	n := int(b.n)
+
+	// order[i] is the index of the i-th row in the result.
+	order := make([]int, n)
+	for i := range order {
+		order[i] = i
+	}
+
+	if opt.SortBy != "" {
+		j := columnIndex(b.schema, opt.SortBy)
+		if j < 0 {
+			return nil, UnknownColumn
+		}
+
+		var less func(x, y int) bool
+		switch b.schema[j].Type {
+		case ColumnU32:
+			vs := b.u32s[j]
+			less = func(x, y int) bool { return vs[x] < vs[y] }
+		case ColumnI64:
+			vs := b.i64s[j]
+			less = func(x, y int) bool { return vs[x] < vs[y] }
+		case ColumnBytes:
+			vs := b.bytes[j]
+			less = func(x, y int) bool { return bytes.Compare(vs[x], vs[y]) < 0 }
+		}
+
+		sort.SliceStable(order, func(x, y int) bool {
+			return less(order[x], order[y])
+		})
+	}
+
+	tb := &SlimTable{
+		Columns:  make([]*TableColumn, len(b.schema)),
+		SortedBy: opt.SortBy,
+	}
+
+	for j, c := range b.schema {
+		col := &TableColumn{
+			Name: c.Name,
+			Type: int32(c.Type),
+		}
+
+		switch c.Type {
+		case ColumnU32:
+			nums := make([]uint32, n)
+			for i, k := range order {
+				nums[i] = b.u32s[j][k]
+			}
+			col.Nums = NewU32Opt(nums, &opt.Opt)
+
+		case ColumnI64:
+			vs := b.i64s[j]
+			max := int64(0)
+			if n > 0 {
+				col.Base, max = vs[0], vs[0]
+				for _, v := range vs {
+					if v < col.Base {
+						col.Base = v
+					}
+					if v > max {
+						max = v
+					}
+				}
+			}
+
+			// A value is stored as v - Base in a SlimArray of uint64, in
+			// which an elt must be smaller than 2^63.
+			if uint64(max)-uint64(col.Base) > math.MaxInt64 {
+				return nil, ValueRangeTooLarge
+			}
+
+			nums := make([]uint64, n)
+			for i, k := range order {
+				nums[i] = uint64(vs[k] - col.Base)
+			}
+			col.Nums = NewU64Opt(nums, &opt.Opt)
+
+		case ColumnBytes:
+			records := make([][]byte, n)
+			for i, k := range order {
+				records[i] = b.bytes[j][k]
+			}
+			sb, err := NewBytes(records)
+			if err != nil {
+				return nil, err
+			}
+			col.Bytes = sb
+		}
+
+		tb.Columns[j] = col
+	}
+
+	return tb, nil
+}
+
+// Len returns the number of rows.
+//
+// Since 0.1.15
+func (tb *SlimTable) Len() int {
+	if len(tb.Columns) == 0 {
+		return 0
+	}
+	c := tb.Columns[0]
+	if ColumnType(c.Type) == ColumnBytes {
+		return c.Bytes.Len()
+	}
+	return c.Nums.Len()
+}
+
+// Schema returns the definition of all columns.
+//
+// Since 0.1.15
+func (tb *SlimTable) Schema() []ColumnDef {
+	schema := make([]ColumnDef, len(tb.Columns))
+	for j, c := range tb.Columns {
+		schema[j] = ColumnDef{Name: c.Name, Type: ColumnType(c.Type)}
+	}
+	return schema
+}
+
+// ColumnIndex returns the index of the column in the schema, or -1 if there is
+// no such column.
+//
+// Since 0.1.15
+func (tb *SlimTable) ColumnIndex(name string) int {
+	for j, c := range tb.Columns {
+		if c.Name == name {
+			return j
+		}
+	}
+	return -1
+}
+
+// U32 returns the value of the j-th column of the i-th row.
+// It panics if the column is not a ColumnU32.
+//
+// Since 0.1.15
+func (tb *SlimTable) U32(j int, i int32) uint32 {
+	c := tb.column(j, ColumnU32)
+	return c.Nums.Get(i)
+}
+
+// I64 returns the value of the j-th column of the i-th row.
+// It panics if the column is not a ColumnI64.
+//
+// Since 0.1.15
+func (tb *SlimTable) I64(j int, i int32) int64 {
+	c := tb.column(j, ColumnI64)
+	return int64(c.Nums.GetU64(i)) + c.Base
+}
+
+// Bytes returns the value of the j-th column of the i-th row.
+// It panics if the column is not a ColumnBytes.
+//
+// Since 0.1.15
+func (tb *SlimTable) Bytes(j int, i int32) []byte {
+	c := tb.column(j, ColumnBytes)
+	return c.Bytes.Get(i)
+}
+
+// Row returns all fields of the i-th row in schema order.
+// A field is a uint32, int64 or []byte, as the type of the column.
+//
+// Since 0.1.15
+func (tb *SlimTable) Row(i int32) []interface{} {
+	row := make([]interface{}, len(tb.Columns))
+	for j := range tb.Columns {
+		row[j] = tb.field(j, i)
+	}
+	return row
+}
+
+// Search returns the index of the first row whose value of the sorted column
+// is greater than or equal to v. It returns Len() if there is no such row.
+// The type of v must be the same as a field of the sorted column.
+//
+// It panics if rows are not sorted, or the type of v does not match.
+//
+// Since 0.1.15
+func (tb *SlimTable) Search(v interface{}) int32 {
+
+	j := tb.ColumnIndex(tb.SortedBy)
+	if tb.SortedBy == "" || j < 0 {
+		panic("slimarray: SlimTable is not sorted")
+	}
+
+	var geq func(i int32) bool
+	switch x := v.(type) {
+	case uint32:
+		geq = func(i int32) bool { return tb.U32(j, i) >= x }
+	case int64:
+		geq = func(i int32) bool { return tb.I64(j, i) >= x }
+	case []byte:
+		geq = func(i int32) bool { return bytes.Compare(tb.Bytes(j, i), x) >= 0 }
+	case string:
+		geq = func(i int32) bool { return bytes.Compare(tb.Bytes(j, i), []byte(x)) >= 0 }
+	default:
+		panic(fmt.Sprintf("slimarray: can not search %T in SlimTable", v))
+	}
+
+	return int32(sort.Search(tb.Len(), func(i int) bool {
+		return geq(int32(i))
+	}))
+}
+
+// Lookup returns the index of the first row whose value of the sorted column
+// equals v and true. If there is no such row, it returns the result of
+// Search(v) and false.
+//
+// It panics in the same cases as Search.
+//
+// Since 0.1.15
+func (tb *SlimTable) Lookup(v interface{}) (int32, bool) {
+
+	i := tb.Search(v)
+	if int(i) == tb.Len() {
+		return i, false
+	}
+
+	j := tb.ColumnIndex(tb.SortedBy)
+	got := tb.field(j, i)
+
+	switch x := v.(type) {
+	case []byte:
+		return i, bytes.Equal(got.([]byte), x)
+	case string:
+		return i, string(got.([]byte)) == x
+	default:
+		return i, got == v
+	}
+}
+
+// field returns the value of the j-th column of the i-th row.
+func (tb *SlimTable) field(j int, i int32) interface{} {
+	switch ColumnType(tb.Columns[j].Type) {
+	case ColumnU32:
+		return tb.U32(j, i)
+	case ColumnI64:
+		return tb.I64(j, i)
+	default:
+		return tb.Bytes(j, i)
+	}
+}
+
+// column returns the j-th column and panics if it is not of type typ.
+func (tb *SlimTable) column(j int, typ ColumnType) *TableColumn {
+	c := tb.Columns[j]
+	if ColumnType(c.Type) != typ {
+		panic(fmt.Sprintf("slimarray: column %q is of type %d, not %d", c.Name, c.Type, typ))
+	}
+	return c
+}
+
+// columnIndex returns the index of the column named name in schema, or -1.
+func columnIndex(schema []ColumnDef, name string) int {
+	for j, c := range schema {
+		if c.Name == name {
+			return j
+		}
+	}
+	return -1
+}
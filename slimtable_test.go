@@ -0,0 +1,289 @@
+package slimarray
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/stretchr/testify/require"
+)
+
+var testSchema = []ColumnDef{
+	{Name: "id", Type: ColumnU32},
+	{Name: "ts", Type: ColumnI64},
+	{Name: "name", Type: ColumnBytes},
+}
+
+func TestNewTableBuilder_error(t *testing.T) {
+
+	ta := require.New(t)
+
+	cases := [][]ColumnDef{
+		nil,
+		{{Name: "", Type: ColumnU32}},
+		{{Name: "a", Type: ColumnU32}, {Name: "a", Type: ColumnI64}},
+		{{Name: "a", Type: 0}},
+		{{Name: "a", Type: ColumnBytes + 1}},
+	}
+
+	for i, schema := range cases {
+		_, err := NewTableBuilder(schema)
+		ta.Equal(InvalidSchema, err, "%d-th case: %v", i+1, schema)
+	}
+}
+
+func TestTableBuilder_Add(t *testing.T) {
+
+	ta := require.New(t)
+
+	b, err := NewTableBuilder(testSchema)
+	ta.NoError(err)
+
+	ta.NoError(b.Add(uint32(1), int64(-5), []byte("foo")))
+	ta.NoError(b.Add(uint32(2), int64(7), "bar"))
+
+	ta.Equal(RowMismatch, b.Add(uint32(3), int64(7)))
+	ta.Equal(RowMismatch, b.Add(uint32(3), int64(7), "x", "y"))
+	ta.Equal(RowMismatch, b.Add(3, int64(7), "x"))
+	ta.Equal(RowMismatch, b.Add(uint32(3), uint32(7), "x"))
+	ta.Equal(RowMismatch, b.Add(uint32(3), int64(7), uint32(1)))
+
+	tb, err := b.Build(nil)
+	ta.NoError(err)
+
+	// failed Add does not add any field
+	ta.Equal(2, tb.Len())
+	ta.Equal([]interface{}{uint32(1), int64(-5), []byte("foo")}, tb.Row(0))
+	ta.Equal([]interface{}{uint32(2), int64(7), []byte("bar")}, tb.Row(1))
+}
+
+func TestSlimTable(t *testing.T) {
+
+	ta := require.New(t)
+
+	rows := [][]interface{}{
+		{uint32(5), int64(1 << 40), "e"},
+		{uint32(1), int64(-1 << 40), "a"},
+		{uint32(3), int64(0), ""},
+		{uint32(1), int64(-1), "b"},
+	}
+
+	b, err := NewTableBuilder(testSchema)
+	ta.NoError(err)
+	for _, r := range rows {
+		ta.NoError(b.Add(r...))
+	}
+
+	tb, err := b.Build(nil)
+	ta.NoError(err)
+
+	ta.Equal(4, tb.Len())
+	ta.Equal(testSchema, tb.Schema())
+	ta.Equal("", tb.SortedBy)
+
+	ta.Equal(0, tb.ColumnIndex("id"))
+	ta.Equal(2, tb.ColumnIndex("name"))
+	ta.Equal(-1, tb.ColumnIndex("foo"))
+
+	for i, r := range rows {
+		ta.Equal(r[0], tb.U32(0, int32(i)))
+		ta.Equal(r[1], tb.I64(1, int32(i)))
+		ta.Equal([]byte(r[2].(string)), tb.Bytes(2, int32(i)))
+	}
+
+	ta.Panics(func() { tb.U32(1, 0) })
+	ta.Panics(func() { tb.I64(2, 0) })
+	ta.Panics(func() { tb.Bytes(0, 0) })
+	ta.Panics(func() { tb.Search(uint32(1)) })
+}
+
+func TestSlimTable_empty(t *testing.T) {
+
+	ta := require.New(t)
+
+	b, err := NewTableBuilder(testSchema)
+	ta.NoError(err)
+
+	tb, err := b.Build(&TableOpt{SortBy: "ts"})
+	ta.NoError(err)
+	ta.Equal(0, tb.Len())
+
+	i, found := tb.Lookup(int64(1))
+	ta.Equal(int32(0), i)
+	ta.False(found)
+
+	ta.Equal(0, (&SlimTable{}).Len())
+}
+
+func TestSlimTable_i64Range(t *testing.T) {
+
+	ta := require.New(t)
+
+	schema := []ColumnDef{{Name: "v", Type: ColumnI64}}
+
+	b, err := NewTableBuilder(schema)
+	ta.NoError(err)
+	ta.NoError(b.Add(int64(math.MinInt64)))
+	ta.NoError(b.Add(int64(math.MaxInt64)))
+
+	_, err = b.Build(nil)
+	ta.Equal(ValueRangeTooLarge, err)
+
+	want := []int64{math.MinInt64, -1, math.MinInt64 + 1, -2}
+
+	b, err = NewTableBuilder(schema)
+	ta.NoError(err)
+	for _, v := range want {
+		ta.NoError(b.Add(v))
+	}
+
+	tb, err := b.Build(nil)
+	ta.NoError(err)
+	for i, v := range want {
+		ta.Equal(v, tb.I64(0, int32(i)), "%d-th", i)
+	}
+}
+
+func TestSlimTable_sorted(t *testing.T) {
+
+	ta := require.New(t)
+
+	b, err := NewTableBuilder(testSchema)
+	ta.NoError(err)
+
+	_, err = b.Build(&TableOpt{SortBy: "foo"})
+	ta.Equal(UnknownColumn, err)
+
+	n := 1000
+	rnd := rand.New(rand.NewSource(0))
+
+	ids := make([]uint32, n)
+	for i := 0; i < n; i++ {
+		ids[i] = uint32(rnd.Intn(n/2) * 2)
+		ta.NoError(b.Add(ids[i], int64(i), fmt.Sprintf("k%06d", ids[i])))
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	for _, col := range []string{"id", "name"} {
+
+		tb, err := b.Build(&TableOpt{SortBy: col})
+		ta.NoError(err)
+		ta.Equal(col, tb.SortedBy)
+
+		for i := 0; i < n; i++ {
+			ta.Equal(ids[i], tb.U32(0, int32(i)))
+			ta.Equal(fmt.Sprintf("k%06d", ids[i]), string(tb.Bytes(2, int32(i))))
+
+			// equal ids keep the order they are added
+			if i > 0 && ids[i] == ids[i-1] {
+				ta.Less(tb.I64(1, int32(i-1)), tb.I64(1, int32(i)))
+			}
+		}
+
+		for id := uint32(0); id < uint32(n)+2; id++ {
+			want := int32(sort.Search(n, func(i int) bool { return ids[i] >= id }))
+			wantFound := int(want) < n && ids[want] == id
+
+			var key interface{} = id
+			if col == "name" {
+				key = fmt.Sprintf("k%06d", id)
+			}
+
+			ta.Equal(want, tb.Search(key), "id: %d", id)
+
+			i, found := tb.Lookup(key)
+			ta.Equal(want, i, "id: %d", id)
+			ta.Equal(wantFound, found, "id: %d", id)
+
+			if col == "name" {
+				i, found = tb.Lookup([]byte(key.(string)))
+				ta.Equal(want, i, "id: %d", id)
+				ta.Equal(wantFound, found, "id: %d", id)
+			}
+		}
+
+		ta.Panics(func() { tb.Search(int64(1)) })
+		ta.Panics(func() { tb.Search(1) })
+	}
+
+	tb, err := b.Build(&TableOpt{SortBy: "ts"})
+	ta.NoError(err)
+
+	i, found := tb.Lookup(int64(n - 1))
+	ta.Equal(int32(n-1), i)
+	ta.True(found)
+
+	i, found = tb.Lookup(int64(-1))
+	ta.Equal(int32(0), i)
+	ta.False(found)
+}
+
+func TestSlimTable_marshalUnmarshal(t *testing.T) {
+
+	ta := require.New(t)
+
+	b, err := NewTableBuilder(testSchema)
+	ta.NoError(err)
+	for i := 0; i < 100; i++ {
+		ta.NoError(b.Add(uint32(100-i), int64(i*i-500), fmt.Sprintf("r%d", i)))
+	}
+
+	tb, err := b.Build(&TableOpt{SortBy: "id"})
+	ta.NoError(err)
+
+	bytes, err := proto.Marshal(tb)
+	ta.NoError(err)
+
+	got := &SlimTable{}
+	err = proto.Unmarshal(bytes, got)
+	ta.NoError(err)
+
+	ta.Equal(tb.Schema(), got.Schema())
+	ta.Equal(tb.Len(), got.Len())
+	for i := int32(0); i < int32(tb.Len()); i++ {
+		ta.Equal(tb.Row(i), got.Row(i))
+	}
+
+	i, found := got.Lookup(uint32(50))
+	ta.True(found)
+	ta.Equal([]interface{}{uint32(50), int64(50*50 - 500), []byte("r50")}, got.Row(i))
+}
+
+func BenchmarkSlimTable(b *testing.B) {
+
+	n := 1024 * 64
+	tbb, _ := NewTableBuilder(testSchema)
+	for i := 0; i < n; i++ {
+		tbb.Add(uint32(i*3), int64(i)*1000, fmt.Sprintf("name-%d", i))
+	}
+	tb, _ := tbb.Build(&TableOpt{SortBy: "id"})
+
+	b.Run("Row", func(b *testing.B) {
+		s := 0
+		for i := 0; i < b.N; i++ {
+			s += len(tb.Row(int32(i % n)))
+		}
+		Output = s
+	})
+
+	b.Run("U32", func(b *testing.B) {
+		s := uint32(0)
+		for i := 0; i < b.N; i++ {
+			s += tb.U32(0, int32(i%n))
+		}
+		Output = int(s)
+	})
+
+	b.Run("Lookup", func(b *testing.B) {
+		s := 0
+		for i := 0; i < b.N; i++ {
+			if _, found := tb.Lookup(uint32(i % (n * 3))); found {
+				s++
+			}
+		}
+		Output = s
+	})
+}
@@ -0,0 +1,147 @@
+package slimarray
+
+import (
+	"errors"
+	"sort"
+	"time"
+)
+
+var (
+	// BeforeEpoch is returned if a timestamp is before the epoch of SlimTimes.
+	BeforeEpoch = errors.New("timestamp is before epoch")
+)
+
+// TimesOpt specifies how to build a SlimTimes.
+//
+// Since 0.1.15
+type TimesOpt struct {
+	// Opt specifies how to build the underlying SlimArray.
+	Opt
+
+	// Epoch is the base time. Every timestamp must not be before it.
+	// By default it is the first timestamp truncated to Resolution.
+	Epoch time.Time
+
+	// Resolution is the unit to store a timestamp, e.g., time.Millisecond.
+	// A timestamp is truncated to a multiple of Resolution since Epoch.
+	// By default it is time.Nanosecond.
+	Resolution time.Duration
+}
+
+// NewTimes creates a SlimTimes from sorted timestamps.
+// opt could be nil to use default options.
+//
+// A timestamp must be in the range of time.Time.UnixNano(), i.e., between
+// year 1678 and 2262.
+// It returns NotSorted if timestamps are not sorted, or BeforeEpoch if a
+// timestamp is before opt.Epoch.
+//
+// Since 0.1.15
+func NewTimes(ts []time.Time, opt *TimesOpt) (*SlimTimes, error) {
+
+	if int64(len(ts)) > 0x7fffffff {
+		return nil, TooManyRows
+	}
+
+	if opt == nil {
+		opt = &TimesOpt{}
+	}
+
+	res := int64(opt.Resolution)
+	if res <= 0 {
+		res = int64(time.Nanosecond)
+	}
+
+	var epoch int64
+	if !opt.Epoch.IsZero() {
+		epoch = opt.Epoch.UnixNano()
+	} else if len(ts) > 0 {
+		epoch = ts[0].UnixNano()
+		epoch -= floorMod(epoch, res)
+	}
+
+	nums := make([]uint64, len(ts))
+	prev := int64(0)
+	for i, t := range ts {
+		d := t.UnixNano() - epoch
+		v := (d - floorMod(d, res)) / res
+
+		if i > 0 && v < prev {
+			return nil, NotSorted
+		}
+		if v < 0 {
+			return nil, BeforeEpoch
+		}
+
+		nums[i] = uint64(v)
+		prev = v
+	}
+
+	st := &SlimTimes{
+		Epoch:      epoch,
+		Resolution: res,
+		Times:      NewU64Opt(nums, &opt.Opt),
+	}
+
+	return st, nil
+}
+
+// Len returns the number of timestamps.
+//
+// Since 0.1.15
+func (st *SlimTimes) Len() int {
+	return st.Times.Len()
+}
+
+// At returns the i-th timestamp, truncated to Resolution.
+//
+// Since 0.1.15
+func (st *SlimTimes) At(i int) time.Time {
+	return time.Unix(0, st.Epoch+int64(st.Times.GetU64(int32(i)))*st.Resolution)
+}
+
+// SearchTime returns the index of the first timestamp that is not before t,
+// i.e., the smallest i that !At(i).Before(t).
+// It returns Len() if every timestamp is before t.
+//
+// Since 0.1.15
+func (st *SlimTimes) SearchTime(t time.Time) int {
+
+	d := t.UnixNano() - st.Epoch
+	if d <= 0 {
+		return 0
+	}
+
+	// the smallest stored value v that Epoch + v*Resolution >= t
+	v := uint64(d / st.Resolution)
+	if d%st.Resolution != 0 {
+		v++
+	}
+
+	return sort.Search(st.Len(), func(i int) bool {
+		return st.Times.GetU64(int32(i)) >= v
+	})
+}
+
+// Range returns the index range [start, end) of timestamps in the time range
+// [from, to).
+//
+// Since 0.1.15
+func (st *SlimTimes) Range(from, to time.Time) (int, int) {
+
+	start := st.SearchTime(from)
+	end := st.SearchTime(to)
+	if end < start {
+		end = start
+	}
+	return start, end
+}
+
+// floorMod returns a mod b that is in [0, b), for positive b.
+func floorMod(a, b int64) int64 {
+	m := a % b
+	if m < 0 {
+		m += b
+	}
+	return m
+}
@@ -0,0 +1,217 @@
+package slimarray
+
+import (
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/openacid/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+// eventTimes returns sorted timestamps about 1 ms apart.
+func eventTimes(n int) []time.Time {
+
+	start := time.Date(2021, 3, 4, 5, 6, 7, 123456789, time.UTC).UnixNano()
+
+	// adjacent elts differ by [0, 2ms)
+	nanos := testutil.RandI64Slice(start, int64(n), int64(2*time.Millisecond))
+
+	ts := make([]time.Time, n)
+	for i, ns := range nanos {
+		// +i to make timestamps distinct
+		ts[i] = time.Unix(0, ns+int64(i))
+	}
+	return ts
+}
+
+func TestNewTimes(t *testing.T) {
+
+	ta := require.New(t)
+
+	ts := eventTimes(5000)
+
+	for _, res := range []time.Duration{0, time.Nanosecond, time.Microsecond, time.Millisecond, time.Second} {
+
+		st, err := NewTimes(ts, &TimesOpt{Resolution: res})
+		ta.NoError(err)
+		ta.Equal(len(ts), st.Len())
+
+		if res == 0 {
+			res = time.Nanosecond
+		}
+		ta.Equal(int64(res), st.Resolution)
+
+		// default epoch is the first timestamp truncated to resolution
+		ta.Equal(ts[0].Truncate(res).UnixNano(), st.Epoch)
+
+		for i, tm := range ts {
+			ta.True(tm.Truncate(res).Equal(st.At(i)), "res: %s, i: %d, %s %s", res, i, tm, st.At(i))
+		}
+
+		t.Logf("resolution: %s: %.3f bits/elt", res, float64(spanBits(st.Times))/float64(len(ts)))
+	}
+}
+
+func TestNewTimes_epoch(t *testing.T) {
+
+	ta := require.New(t)
+
+	ts := eventTimes(100)
+	epoch := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	st, err := NewTimes(ts, &TimesOpt{Epoch: epoch, Resolution: time.Microsecond})
+	ta.NoError(err)
+	ta.Equal(epoch.UnixNano(), st.Epoch)
+
+	for i, tm := range ts {
+		ta.True(tm.Truncate(time.Microsecond).Equal(st.At(i)))
+	}
+
+	// an epoch before 1970
+	epoch = time.Date(1960, 1, 1, 0, 0, 0, 0, time.UTC)
+	st, err = NewTimes(ts, &TimesOpt{Epoch: epoch, Resolution: time.Second})
+	ta.NoError(err)
+	for i, tm := range ts {
+		ta.True(tm.Truncate(time.Second).Equal(st.At(i)))
+	}
+
+	_, err = NewTimes(ts, &TimesOpt{Epoch: ts[1]})
+	ta.Equal(BeforeEpoch, err)
+}
+
+func TestNewTimes_error(t *testing.T) {
+
+	ta := require.New(t)
+
+	now := time.Now()
+
+	_, err := NewTimes([]time.Time{now, now.Add(-1)}, nil)
+	ta.Equal(NotSorted, err)
+
+	// equal after truncation is sorted
+	st, err := NewTimes([]time.Time{now, now.Add(-1)}, &TimesOpt{Resolution: time.Hour})
+	ta.NoError(err)
+	ta.Equal(2, st.Len())
+
+	st, err = NewTimes(nil, nil)
+	ta.NoError(err)
+	ta.Equal(0, st.Len())
+	ta.Equal(0, st.SearchTime(now))
+}
+
+func TestSlimTimes_SearchTime(t *testing.T) {
+
+	ta := require.New(t)
+
+	ts := eventTimes(3000)
+	// duplicates
+	ts = append(ts, ts[len(ts)-1], ts[len(ts)-1])
+
+	for _, res := range []time.Duration{time.Nanosecond, time.Microsecond, time.Second} {
+
+		st, err := NewTimes(ts, &TimesOpt{Resolution: res})
+		ta.NoError(err)
+
+		truncated := make([]time.Time, len(ts))
+		for i, tm := range ts {
+			truncated[i] = tm.Truncate(res)
+		}
+
+		search := func(tm time.Time) int {
+			return sort.Search(len(truncated), func(i int) bool { return !truncated[i].Before(tm) })
+		}
+
+		probes := []time.Time{
+			ts[0].Add(-time.Hour),
+			ts[0],
+			ts[len(ts)-1],
+			ts[len(ts)-1].Add(1),
+			ts[len(ts)-1].Add(time.Hour),
+		}
+		for i := 0; i < len(ts); i += 7 {
+			probes = append(probes, ts[i], ts[i].Add(-1), ts[i].Add(1), truncated[i], truncated[i].Add(1))
+		}
+
+		for _, p := range probes {
+			ta.Equal(search(p), st.SearchTime(p), "res: %s, probe: %s", res, p)
+		}
+	}
+}
+
+func TestSlimTimes_Range(t *testing.T) {
+
+	ta := require.New(t)
+
+	ts := eventTimes(1000)
+
+	st, err := NewTimes(ts, nil)
+	ta.NoError(err)
+
+	s, e := st.Range(ts[10], ts[20])
+	ta.Equal(10, s)
+	ta.Equal(20, e)
+
+	s, e = st.Range(ts[10], ts[20].Add(1))
+	ta.Equal(10, s)
+	ta.Equal(21, e)
+
+	s, e = st.Range(ts[0].Add(-time.Hour), ts[len(ts)-1].Add(time.Hour))
+	ta.Equal(0, s)
+	ta.Equal(len(ts), e)
+
+	// empty ranges
+	s, e = st.Range(ts[20], ts[10])
+	ta.Equal(s, e)
+
+	s, e = st.Range(ts[len(ts)-1].Add(1), ts[len(ts)-1].Add(time.Hour))
+	ta.Equal(len(ts), s)
+	ta.Equal(len(ts), e)
+}
+
+func TestSlimTimes_marshalUnmarshal(t *testing.T) {
+
+	ta := require.New(t)
+
+	ts := eventTimes(2000)
+
+	st, err := NewTimes(ts, &TimesOpt{Resolution: time.Microsecond})
+	ta.NoError(err)
+
+	bytes, err := proto.Marshal(st)
+	ta.NoError(err)
+
+	b := &SlimTimes{}
+	err = proto.Unmarshal(bytes, b)
+	ta.NoError(err)
+
+	ta.Equal(st.Epoch, b.Epoch)
+	ta.Equal(int64(time.Microsecond), b.Resolution)
+
+	for i := range ts {
+		ta.True(st.At(i).Equal(b.At(i)))
+	}
+}
+
+func BenchmarkSlimTimes(b *testing.B) {
+
+	ts := eventTimes(1024*64)
+	st, _ := NewTimes(ts, nil)
+
+	b.Run("At", func(b *testing.B) {
+		var s int64
+		for i := 0; i < b.N; i++ {
+			s += st.At(i & 0xffff).UnixNano()
+		}
+		Output = int(s)
+	})
+
+	b.Run("SearchTime", func(b *testing.B) {
+		s := 0
+		for i := 0; i < b.N; i++ {
+			s += st.SearchTime(ts[i&0xffff])
+		}
+		Output = s
+	})
+}
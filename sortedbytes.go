@@ -0,0 +1,146 @@
+package slimarray
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"sort"
+)
+
+const (
+	// DefaultBlockSize is the default number of records in a front-coding
+	// block of SortedBytes.
+	DefaultBlockSize = 16
+)
+
+var (
+	NotSorted = errors.New("records are not sorted")
+)
+
+// NewSortedBytes creates a SortedBytes from sorted records with front coding:
+// The first record in every blockSize records is stored in full, and the others
+// are stored as the length of prefix shared with the preceding record and the
+// rest suffix.
+//
+// If blockSize is 0, DefaultBlockSize is used.
+// A larger blockSize results in less memory and slower Get.
+//
+// Since 0.1.15
+func NewSortedBytes(records [][]byte, blockSize int32) (*SortedBytes, error) {
+
+	if blockSize <= 0 {
+		blockSize = DefaultBlockSize
+	}
+
+	b := NewBytesBuilder(0)
+	entry := make([]byte, 0, 64)
+	lbuf := make([]byte, binary.MaxVarintLen64)
+
+	for i, rec := range records {
+
+		if i > 0 && bytes.Compare(records[i-1], rec) > 0 {
+			return nil, NotSorted
+		}
+
+		if int32(i)%blockSize == 0 {
+			entry = append(entry[:0], rec...)
+		} else {
+			shared := sharedPrefixLen(records[i-1], rec)
+			l := binary.PutUvarint(lbuf, uint64(shared))
+			entry = append(entry[:0], lbuf[:l]...)
+			entry = append(entry, rec[shared:]...)
+		}
+
+		err := b.Add(entry)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	sb := &SortedBytes{
+		BlockSize: blockSize,
+		Entries:   b.Finish(),
+	}
+	return sb, nil
+}
+
+// Len returns the number of records.
+//
+// Since 0.1.15
+func (sb *SortedBytes) Len() int {
+	return sb.Entries.Len()
+}
+
+// Get returns the i-th record.
+// The returned slice is newly allocated.
+//
+// Since 0.1.15
+func (sb *SortedBytes) Get(i int32) []byte {
+	head := i - i%sb.BlockSize
+	rec := append([]byte{}, sb.Entries.Get(head)...)
+	for j := head + 1; j <= i; j++ {
+		rec = sb.decode(rec, j)
+	}
+	return rec
+}
+
+// Search returns the index of the first record that is greater than or equal
+// to key. It returns Len() if there is no such record.
+//
+// It binary searches the first record of every block, then scans in a block.
+//
+// Since 0.1.15
+func (sb *SortedBytes) Search(key []byte) int32 {
+
+	n := int32(sb.Len())
+	nBlocks := int((n + sb.BlockSize - 1) / sb.BlockSize)
+
+	// the first block whose head is greater than or equal to key.
+	blk := int32(sort.Search(nBlocks, func(i int) bool {
+		return bytes.Compare(sb.Entries.Get(int32(i)*sb.BlockSize), key) >= 0
+	}))
+
+	if blk == 0 {
+		return 0
+	}
+
+	// The result is in block blk-1 or is the head of block blk.
+	i := (blk - 1) * sb.BlockSize
+	end := i + sb.BlockSize
+	if end > n {
+		end = n
+	}
+
+	rec := append([]byte{}, sb.Entries.Get(i)...)
+	for {
+		if bytes.Compare(rec, key) >= 0 {
+			return i
+		}
+		i++
+		if i == end {
+			return i
+		}
+		rec = sb.decode(rec, i)
+	}
+}
+
+// decode the i-th record from the preceding record prev.
+func (sb *SortedBytes) decode(prev []byte, i int32) []byte {
+	entry := sb.Entries.Get(i)
+	shared, l := binary.Uvarint(entry)
+	return append(prev[:shared], entry[l:]...)
+}
+
+func sharedPrefixLen(a, b []byte) int {
+	n := len(a)
+	if len(b) < n {
+		n = len(b)
+	}
+
+	for i := 0; i < n; i++ {
+		if a[i] != b[i] {
+			return i
+		}
+	}
+	return n
+}
@@ -0,0 +1,168 @@
+package slimarray
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/openacid/low/size"
+	"github.com/openacid/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewSortedBytes(t *testing.T) {
+
+	ta := require.New(t)
+
+	_, err := NewSortedBytes([][]byte{[]byte("b"), []byte("a")}, 4)
+	ta.Equal(NotSorted, err)
+
+	sb, err := NewSortedBytes(nil, 0)
+	ta.NoError(err)
+	ta.Equal(int32(DefaultBlockSize), sb.BlockSize)
+	ta.Equal(0, sb.Len())
+	ta.Equal(int32(0), sb.Search([]byte("a")))
+}
+
+func TestSortedBytes_Get(t *testing.T) {
+
+	ta := require.New(t)
+
+	records := sortedRecords(1024 * 3)
+
+	for _, blockSize := range []int32{1, 2, 3, 16, 64} {
+
+		sb, err := NewSortedBytes(records, blockSize)
+		ta.NoError(err)
+		ta.Equal(len(records), sb.Len())
+
+		for i, rec := range records {
+			ta.Equal(rec, sb.Get(int32(i)), "blockSize=%d, i=%d", blockSize, i)
+		}
+	}
+}
+
+func TestSortedBytes_Search(t *testing.T) {
+
+	ta := require.New(t)
+
+	records := [][]byte{}
+	for _, k := range []string{"", "a", "a", "a", "ab", "abc", "abc", "b", "ba", "bb", "c"} {
+		records = append(records, []byte(k))
+	}
+
+	for _, blockSize := range []int32{1, 2, 3, 4, 16} {
+
+		sb, err := NewSortedBytes(records, blockSize)
+		ta.NoError(err)
+
+		for _, k := range []string{"", "0", "a", "aa", "ab", "abc", "abd", "b", "ba", "bc", "c", "d"} {
+			key := []byte(k)
+			want := sort.Search(len(records), func(i int) bool {
+				return bytes.Compare(records[i], key) >= 0
+			})
+			got := sb.Search(key)
+			ta.Equal(int32(want), got, "blockSize=%d, key=%q", blockSize, k)
+		}
+	}
+}
+
+func TestSortedBytes_Search_big(t *testing.T) {
+
+	ta := require.New(t)
+
+	records := sortedRecords(1024 * 10)
+
+	sb, err := NewSortedBytes(records, 0)
+	ta.NoError(err)
+
+	for i, rec := range records {
+		ta.Equal(int32(i), sb.Search(rec))
+	}
+}
+
+func TestSortedBytes_memory(t *testing.T) {
+
+	ta := require.New(t)
+
+	records := sortedRecords(1024 * 100)
+
+	plain, err := NewBytes(records)
+	ta.NoError(err)
+
+	sb, err := NewSortedBytes(records, 0)
+	ta.NoError(err)
+
+	fmt.Println("plain:", size.Of(plain), "front-coded:", size.Of(sb))
+	ta.Less(size.Of(sb)*2, size.Of(plain))
+}
+
+func TestSortedBytes_marshalUnmarshal(t *testing.T) {
+
+	ta := require.New(t)
+
+	records := sortedRecords(1000)
+	sb, err := NewSortedBytes(records, 0)
+	ta.NoError(err)
+
+	bs, err := proto.Marshal(sb)
+	ta.NoError(err)
+
+	b := &SortedBytes{}
+	err = proto.Unmarshal(bs, b)
+	ta.NoError(err)
+
+	for i, rec := range records {
+		ta.Equal(rec, b.Get(int32(i)))
+	}
+}
+
+// sortedRecords makes URL-like sorted records.
+func sortedRecords(n int) [][]byte {
+	records := [][]byte{}
+	for i := 0; i < n; i++ {
+		rec := fmt.Sprintf("https://example.com/users/%06d/posts/%d", i/7, i%7)
+		records = append(records, []byte(rec))
+	}
+	return records
+}
+
+var OutputSortedBytes int
+
+func BenchmarkSortedBytes_Get(b *testing.B) {
+
+	records := testutil.RandBytesSlice(1024*1024, 10, 20)
+	sort.Slice(records, func(i, j int) bool { return bytes.Compare(records[i], records[j]) < 0 })
+
+	sb, _ := NewSortedBytes(records, 0)
+	mask := len(records) - 1
+
+	b.ResetTimer()
+
+	s := 0
+	for i := 0; i < b.N; i++ {
+		s += len(sb.Get(int32(i & mask)))
+	}
+
+	OutputSortedBytes = s
+}
+
+func BenchmarkSortedBytes_Search(b *testing.B) {
+
+	records := testutil.RandBytesSlice(1024*1024, 10, 20)
+	sort.Slice(records, func(i, j int) bool { return bytes.Compare(records[i], records[j]) < 0 })
+
+	sb, _ := NewSortedBytes(records, 0)
+	mask := len(records) - 1
+
+	b.ResetTimer()
+
+	s := 0
+	for i := 0; i < b.N; i++ {
+		s += int(sb.Search(records[i&mask]))
+	}
+
+	OutputSortedBytes = s
+}
@@ -0,0 +1,195 @@
+package slimarray
+
+import (
+	"fmt"
+
+	"github.com/openacid/low/size"
+)
+
+// Stats describes the memory usage of a SlimArray.
+// All memory sizes are in bytes.
+//
+// Since 0.1.15
+type Stats struct {
+	// N is the total elt count.
+	N int64
+	// SegCnt is the segment count.
+	SegCnt int64
+	// SpanCnt is the total count of spans.
+	SpanCnt int64
+	// EltWidth is the average residual width of spans.
+	EltWidth int64
+
+	// MemTotal is the total memory cost.
+	MemTotal int64
+	// MemRank is the memory cost of SlimArray.Rank.
+	MemRank int64
+	// MemBitmap is the memory cost of SlimArray.Bitmap.
+	MemBitmap int64
+	// MemPolynomials is the memory cost of SlimArray.Polynomials.
+	MemPolynomials int64
+	// MemConfigs is the memory cost of SlimArray.Configs.
+	MemConfigs int64
+	// MemResiduals is the memory cost of SlimArray.Residuals.
+	MemResiduals int64
+
+	// ResidualWidths maps a residual width to the number of elts having this
+	// residual width.
+	ResidualWidths map[int32]int64
+
+	// SpanLens maps a span length, i.e., number of elts in a span, to the
+	// number of spans of this length.
+	SpanLens map[int32]int64
+
+	// CompressionRatio is the size of the uncompressed array, i.e.,
+	// []uint32 or []uint64, divided by MemTotal.
+	CompressionRatio float64
+}
+
+// Stats returns the memory usage of a SlimArray.
+//
+// Since 0.1.15
+func (sm *SlimArray) Stats() *Stats {
+
+	st := &Stats{
+		N:       int64(sm.N),
+		SegCnt:  int64(len(sm.Bitmap)),
+		SpanCnt: int64(len(sm.Polynomials) / polyCoefCnt),
+
+		MemTotal:       int64(size.Of(sm)),
+		MemRank:        int64(len(sm.Rank) * 8),
+		MemBitmap:      int64(len(sm.Bitmap) * 8),
+		MemPolynomials: int64(len(sm.Polynomials) * 8),
+		MemConfigs:     int64(len(sm.Configs) * 8),
+		MemResiduals:   int64(len(sm.Residuals) * 8),
+
+		ResidualWidths: map[int32]int64{},
+		SpanLens:       map[int32]int64{},
+	}
+
+	for _, seg := range sm.Segments() {
+		for _, sp := range seg.Spans {
+			n := sp.End - sp.Start
+			st.EltWidth += int64(sp.ResidualWidth)
+			st.ResidualWidths[sp.ResidualWidth] += int64(n)
+			st.SpanLens[n]++
+		}
+	}
+
+	if st.SpanCnt > 0 {
+		st.EltWidth /= st.SpanCnt
+	}
+
+	eltSize := int64(4)
+	if sm.EltWidth == 64 {
+		eltSize = 8
+	}
+	if st.MemTotal > 0 {
+		st.CompressionRatio = float64(st.N*eltSize) / float64(st.MemTotal)
+	}
+
+	return st
+}
+
+// BitsPerElt returns the average memory cost in bits per elt.
+//
+// Since 0.1.15
+func (st *Stats) BitsPerElt() float64 {
+	if st.N == 0 {
+		return 0
+	}
+	return float64(st.MemTotal*8) / float64(st.N)
+}
+
+// statMap returns the map returned by SlimArray.Stat().
+func (st *Stats) statMap() map[string]int64 {
+
+	n := st.N
+	if n == 0 {
+		n = 1
+	}
+
+	spanCnt := st.SpanCnt
+	if spanCnt == 0 {
+		spanCnt = 1
+	}
+
+	return map[string]int64{
+		"seg_cnt":   st.SegCnt,
+		"elt_width": st.EltWidth,
+		"mem_total": st.MemTotal,
+		"mem_elts":  st.MemResiduals,
+		"bits/elt":  st.MemTotal * 8 / n,
+		"spans/seg": (spanCnt * 1000) / (st.SegCnt*1000 + 1),
+		"span_cnt":  spanCnt,
+		"n":         st.N,
+	}
+}
+
+// String returns the same format as printing the map returned by
+// SlimArray.Stat(), e.g.:
+//
+//    map[bits/elt:11 elt_width:3 mem_elts:160 mem_total:512 n:354 seg_cnt:1 span_cnt:5 spans/seg:4]
+//
+// Since 0.1.15
+func (st *Stats) String() string {
+	return fmt.Sprint(st.statMap())
+}
+
+// BytesStats describes the memory usage of a SlimBytes.
+// All memory sizes are in bytes.
+//
+// Since 0.1.15
+type BytesStats struct {
+	// N is the total record count.
+	N int64
+
+	// MemTotal is the total memory cost.
+	MemTotal int64
+	// MemRecords is the memory cost of SlimBytes.Records.
+	MemRecords int64
+	// MemPositions is the memory cost of SlimBytes.Positions.
+	MemPositions int64
+
+	// Positions is the memory usage of SlimBytes.Positions.
+	Positions *Stats
+}
+
+// Stats returns the memory usage of a SlimBytes.
+//
+// Since 0.1.15
+func (b *SlimBytes) Stats() *BytesStats {
+	return &BytesStats{
+		N:            int64(b.Len()),
+		MemTotal:     int64(size.Of(b)),
+		MemRecords:   int64(len(b.Records)),
+		MemPositions: int64(size.Of(b.Positions)),
+		Positions:    b.Positions.Stats(),
+	}
+}
+
+// OverheadBitsPerElt returns the average memory cost in bits per record
+// except the record itself.
+//
+// Since 0.1.15
+func (st *BytesStats) OverheadBitsPerElt() float64 {
+	if st.N == 0 {
+		return 0
+	}
+	return float64((st.MemTotal-st.MemRecords)*8) / float64(st.N)
+}
+
+// String returns a map style description, e.g.:
+//
+//    map[mem_positions:1088 mem_records:5120 mem_total:6264 n:1024 overhead_bits/elt:8]
+//
+// Since 0.1.15
+func (st *BytesStats) String() string {
+	return fmt.Sprint(map[string]int64{
+		"n":                 st.N,
+		"mem_total":         st.MemTotal,
+		"mem_records":       st.MemRecords,
+		"mem_positions":     st.MemPositions,
+		"overhead_bits/elt": int64(st.OverheadBitsPerElt()),
+	})
+}
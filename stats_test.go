@@ -0,0 +1,102 @@
+package slimarray
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/openacid/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSlimArray_Stats(t *testing.T) {
+
+	ta := require.New(t)
+
+	a := NewU32(testNums)
+	st := a.Stats()
+
+	ta.Equal(int64(354), st.N)
+	ta.Equal(int64(1), st.SegCnt)
+	ta.Equal(int64(5), st.SpanCnt)
+	ta.Equal(int64(3), st.EltWidth)
+	ta.Equal(int64(160), st.MemResiduals)
+	ta.Equal(int64(8), st.MemRank)
+	ta.Equal(int64(8), st.MemBitmap)
+	ta.Equal(int64(5*3*8), st.MemPolynomials)
+	ta.Equal(int64(5*8), st.MemConfigs)
+
+	ta.True(st.MemTotal > st.MemRank+st.MemBitmap+st.MemPolynomials+st.MemConfigs+st.MemResiduals)
+	ta.InDelta(float64(354*4)/float64(st.MemTotal), st.CompressionRatio, 0.0001)
+	ta.InDelta(float64(st.MemTotal*8)/354, st.BitsPerElt(), 0.0001)
+
+	eltCnt := int64(0)
+	for _, cnt := range st.ResidualWidths {
+		eltCnt += cnt
+	}
+	ta.Equal(st.N, eltCnt)
+
+	spanCnt := int64(0)
+	eltCnt = 0
+	for l, cnt := range st.SpanLens {
+		ta.True(l <= segSize)
+		spanCnt += cnt
+		eltCnt += int64(l) * cnt
+	}
+	ta.Equal(st.SpanCnt, spanCnt)
+	ta.Equal(st.N, eltCnt)
+
+	ta.Equal(fmt.Sprint(a.Stat()), st.String())
+}
+
+func TestSlimArray_Stats_cases(t *testing.T) {
+
+	ta := require.New(t)
+
+	cases := [][]uint32{
+		{},
+		{1},
+		testNums[:17],
+		testutil.RandU32Slice(0, 1024*3+5, 100),
+	}
+
+	for i, nums := range cases {
+		a := NewU32(nums)
+		st := a.Stats()
+
+		ta.Equal(int64(len(nums)), st.N, "%d-th", i+1)
+		ta.Equal(fmt.Sprint(a.Stat()), st.String(), "%d-th", i+1)
+
+		eltCnt := int64(0)
+		for l, cnt := range st.SpanLens {
+			eltCnt += int64(l) * cnt
+		}
+		ta.Equal(st.N, eltCnt, "%d-th", i+1)
+	}
+
+	{
+		nums := []uint64{1 << 40, 1<<40 + 3}
+		st := NewU64(nums).Stats()
+		ta.InDelta(float64(2*8)/float64(st.MemTotal), st.CompressionRatio, 0.0001)
+	}
+}
+
+func TestSlimBytes_Stats(t *testing.T) {
+
+	ta := require.New(t)
+
+	records := testutil.RandBytesSlice(1024, 5, 6)
+	sb, err := NewBytes(records)
+	ta.NoError(err)
+
+	st := sb.Stats()
+	fmt.Println(st)
+
+	ta.Equal(int64(1024), st.N)
+	ta.Equal(int64(1024*5), st.MemRecords)
+	ta.Equal(int64(1025), st.Positions.N)
+	ta.True(st.MemTotal > st.MemRecords+st.MemPositions)
+	ta.True(st.OverheadBitsPerElt() < 16)
+
+	ta.Contains(st.String(), "n:1024")
+	ta.Contains(st.String(), "mem_records:5120")
+}